@@ -1,8 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	// Embedded zone data keeps RESPONSE_TIMEZONE working in images without /usr/share/zoneinfo
+	_ "time/tzdata"
 
 	"github.com/abhay2133/api21/config"
 	deliveryHttp "github.com/abhay2133/api21/internal/delivery/http"
@@ -12,53 +21,196 @@ import (
 	"github.com/abhay2133/api21/internal/repository"
 	"github.com/abhay2133/api21/internal/usecase"
 	"github.com/abhay2133/api21/services"
+	"gorm.io/gorm"
 )
 
+// shutdownTimeout bounds how long in-flight requests get to finish once draining is over
+const shutdownTimeout = 10 * time.Second
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCommand(os.Args[2:])
+		return
+	}
+
+	startup := services.NewLifecycle("startup")
+
 	// 1. Load config
 	config.Load()
+	if err := config.AppConfig.Validate(); err != nil {
+		if config.AppConfig.StrictConfig {
+			log.Fatalf("[main] fatal: %v", err)
+		}
+		log.Printf("[main] warning: %v", err)
+	}
+	startup.Mark("config")
 
 	// 2. Init Database (GORM + Postgres)
-	dbConn, err := database.NewPostgresConnection(config.AppConfig.DatabaseURL)
+	dbConn, err := database.NewPostgresConnection(config.AppConfig.DatabaseURL, database.Options{
+		TablePrefix: config.AppConfig.DBTablePrefix,
+		Migrations: database.MigrationOptions{
+			RollbackOnFailure: config.AppConfig.MigrateRollbackOnFailure,
+			Lock:              config.AppConfig.MigrateLock,
+		},
+	})
 	if err != nil {
 		log.Fatalf("[main] fatal: failed to initialize database: %v", err)
 	}
+	startup.Mark("database and migrations")
+
+	if config.AppConfig.DBTagQueries {
+		if err := database.RegisterQueryTagging(dbConn); err != nil {
+			log.Fatalf("[main] fatal: failed to enable query tagging: %v", err)
+		}
+	}
+
+	// 2a. Keep idle pooled connections warm (if configured)
+	if sqlDB, err := dbConn.DB(); err == nil {
+		services.StartDBKeepalive(sqlDB, config.AppConfig.DBKeepaliveInterval)
+	} else {
+		log.Printf("[main] warning: could not access database pool for keepalive: %v", err)
+	}
+
+	// 2b. Route user reads to a replica (if configured), otherwise to the primary
+	readDBConn := dbConn
+	if config.AppConfig.DatabaseReadURL != "" {
+		readDBConn, err = database.NewPostgresReadConnection(config.AppConfig.DatabaseReadURL, database.Options{
+			TablePrefix: config.AppConfig.DBTablePrefix,
+		})
+		if err != nil {
+			log.Fatalf("[main] fatal: failed to connect to read replica: %v", err)
+		}
+		if config.AppConfig.DBTagQueries {
+			if err := database.RegisterQueryTagging(readDBConn); err != nil {
+				log.Fatalf("[main] fatal: failed to enable query tagging on read replica: %v", err)
+			}
+		}
+		startup.Mark("read replica")
+	}
 
 	// 3. Init Redis connection
 	redisClient, err := redis.NewRedisConnection(config.AppConfig.RedisURL)
 	if err != nil {
 		log.Printf("[main] warning: failed to connect to Redis: %v. Proceeding without rate limiting features.", err)
 	}
+	startup.Mark("redis")
 
 	// 4. Start background ping worker (if configured)
-	services.StartPingWorker(config.AppConfig.PingURL)
+	pingWorker := services.StartPingWorker(config.AppConfig.PingURL, config.AppConfig.AllowInternalPing)
+
+	// 4a. Track per-IP request counts for the admin traffic view (if configured)
+	trafficCounter := services.StartTrafficCounter(config.AppConfig.TrafficWindow)
+	latencyTracker := services.NewLatencyTracker()
+	sizeHistogram := services.NewSizeHistogram(config.AppConfig.SizeHistogramBuckets)
+	startup.Mark("background services")
 
 	// 5. Wire layers (Dependency Injection)
-	userRepo := repository.NewUserPostgresRepository(dbConn)
+	userRepo := repository.NewUserPostgresRepository(dbConn, readDBConn)
 	userUsecase := usecase.NewUserUsecase(userRepo)
 	
 	sessionRepo := repository.NewSessionPostgresRepository(dbConn)
 	sessionUsecase := usecase.NewSessionUsecase(sessionRepo)
 
-	userHandler := handler.NewUserHandler(userUsecase)
+	// Validate already reported an unknown zone, so fall back to the stored timestamps here
+	var responseLocation *time.Location
+	if tz := config.AppConfig.ResponseTimezone; tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			responseLocation = loc
+		}
+	}
+
+	userHandler := handler.NewUserHandler(userUsecase, config.AppConfig.StringIDs, responseLocation)
 	healthHandler := handler.NewHealthHandler(dbConn, redisClient)
-	adminHandler := handler.NewAdminHandler(sessionUsecase)
+	migrationPlanner := func(ctx context.Context) ([]string, error) {
+		return database.PendingMigrations(ctx, dbConn, config.AppConfig.DBTablePrefix)
+	}
+	adminHandler := handler.NewAdminHandler(sessionUsecase, pingWorker, trafficCounter, latencyTracker, sizeHistogram, migrationPlanner)
 
 	// 6. Setup Gin Router & register handlers
 	router := deliveryHttp.NewRouter(
 		config.AppConfig.Env,
+		config.AppConfig.MaxConcurrentRequests,
 		dbConn,
 		redisClient,
 		userHandler,
 		healthHandler,
 		adminHandler,
 		sessionUsecase,
+		trafficCounter,
+		latencyTracker,
+		sizeHistogram,
 	)
+	startup.Mark("router")
 
-	// 7. Start the HTTP server
+	// 7. Report ready once connections are warm and STARTUP_GRACE has passed since the process
+	// began starting (migrations already ran above)
+	warmup := func(ctx context.Context) error {
+		for _, conn := range []*gorm.DB{dbConn, readDBConn} {
+			if sqlDB, err := conn.DB(); err == nil {
+				if err := sqlDB.PingContext(ctx); err != nil {
+					return err
+				}
+			}
+		}
+		if redisClient != nil {
+			return redisClient.Ping(ctx).Err()
+		}
+		return nil
+	}
+	healthHandler.MarkReadyAfter(startup.Started(), config.AppConfig.StartupGrace, warmup)
+
+	// 8. Start the HTTP server
 	addr := fmt.Sprintf(":%d", config.AppConfig.Port)
-	log.Printf("[main] Server running at http://localhost%s in %s mode", addr, config.AppConfig.Env)
-	if err := router.Run(addr); err != nil {
-		log.Fatalf("[main] fatal: failed to start server: %v", err)
+	srv := &http.Server{Addr: addr, Handler: router}
+	go func() {
+		log.Printf("[main] Server running at http://localhost%s in %s mode", addr, config.AppConfig.Env)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("[main] fatal: failed to start server: %v", err)
+		}
+	}()
+	startup.LogSummary()
+
+	// 9. On SIGINT/SIGTERM, drop readiness and drain before shutting down
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	shutdown := services.NewLifecycle("shutdown")
+	ctx, cancel := context.WithTimeout(context.Background(), config.AppConfig.DrainPeriod+shutdownTimeout)
+	defer cancel()
+	err = services.DrainAndShutdown(ctx, config.AppConfig.DrainPeriod, healthHandler.MarkNotReady, func(ctx context.Context) error {
+		shutdown.Mark("drain")
+		err := srv.Shutdown(ctx)
+		shutdown.Mark("http server")
+		return err
+	})
+	if err != nil {
+		log.Printf("[main] error during shutdown: %v", err)
+	}
+	shutdown.LogSummary()
+	log.Println("[main] server stopped")
+}
+
+// runMigrateCommand handles `migrate plan`, which lists pending migrations without applying them
+func runMigrateCommand(args []string) {
+	if len(args) != 1 || args[0] != "plan" {
+		log.Fatalf("[migrate] usage: %s migrate plan", os.Args[0])
+	}
+
+	config.Load()
+	pending, err := database.PlanPostgresMigrations(context.Background(), config.AppConfig.DatabaseURL, database.Options{
+		TablePrefix: config.AppConfig.DBTablePrefix,
+	})
+	if err != nil {
+		log.Fatalf("[migrate] fatal: failed to plan migrations: %v", err)
+	}
+
+	if len(pending) == 0 {
+		fmt.Println("No pending migrations, the database is up to date.")
+		return
+	}
+	fmt.Printf("%d pending migration(s):\n", len(pending))
+	for _, name := range pending {
+		fmt.Printf("  %s\n", name)
 	}
 }
@@ -1,15 +1,18 @@
 package handler
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"strconv"
 	"time"
 
 	"github.com/abhay2133/api21/config"
 	"github.com/abhay2133/api21/internal/domain"
+	"github.com/abhay2133/api21/services"
 	"github.com/creack/pty"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -19,13 +22,26 @@ import (
 	"github.com/shirou/gopsutil/v3/mem"
 )
 
+// MigrationPlanner lists the database migrations that have not been applied yet
+type MigrationPlanner func(ctx context.Context) ([]string, error)
+
 type AdminHandler struct {
 	sessionUsecase domain.SessionUsecase
+	pingWorker     *services.PingWorker
+	traffic        *services.TrafficCounter
+	latency        *services.LatencyTracker
+	sizes          *services.SizeHistogram
+	migrations     MigrationPlanner
 }
 
-func NewAdminHandler(sessionUsecase domain.SessionUsecase) *AdminHandler {
+func NewAdminHandler(sessionUsecase domain.SessionUsecase, pingWorker *services.PingWorker, traffic *services.TrafficCounter, latency *services.LatencyTracker, sizes *services.SizeHistogram, migrations MigrationPlanner) *AdminHandler {
 	return &AdminHandler{
 		sessionUsecase: sessionUsecase,
+		pingWorker:     pingWorker,
+		traffic:        traffic,
+		latency:        latency,
+		sizes:          sizes,
+		migrations:     migrations,
 	}
 }
 
@@ -38,8 +54,8 @@ type LoginRequest struct {
 // Login authenticates credentials, deactivates older sessions if Stay Logged in is false, and returns opaque token
 func (h *AdminHandler) Login(c *gin.Context) {
 	var req LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := bindJSON(c, &req); err != nil {
+		c.JSON(bindErrorStatus(err), gin.H{"error": "Invalid request body"})
 		return
 	}
 
@@ -142,7 +158,7 @@ func (h *AdminHandler) GetSystemMetrics(c *gin.Context) {
 		cpuUsage = cPercent[0]
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	metrics := gin.H{
 		"ram": gin.H{
 			"total":       v.Total,
 			"used":        v.Used,
@@ -156,6 +172,84 @@ func (h *AdminHandler) GetSystemMetrics(c *gin.Context) {
 			"used":        d.Used,
 			"usedPercent": d.UsedPercent,
 		},
+	}
+	if h.sizes != nil {
+		metrics["bodySizes"] = h.sizes.Snapshot()
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+// GetPingHistory returns the recent runs of the background ping worker, oldest first
+func (h *AdminHandler) GetPingHistory(c *gin.Context) {
+	if h.pingWorker == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"enabled": false,
+			"runs":    []services.PingRun{},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled": true,
+		"runs":    h.pingWorker.History(),
+	})
+}
+
+// GetTopIPs returns the busiest client IPs in the traffic window, limited by ?limit= (default 10)
+func (h *AdminHandler) GetTopIPs(c *gin.Context) {
+	if h.traffic == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"enabled": false,
+			"ips":     []services.IPTraffic{},
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled": true,
+		"ips":     h.traffic.Top(limit),
+	})
+}
+
+// GetLatency returns per-route latency stats, slowest p95 first
+func (h *AdminHandler) GetLatency(c *gin.Context) {
+	if h.latency == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"enabled": false,
+			"routes":  []services.RouteLatency{},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled": true,
+		"routes":  h.latency.Snapshot(),
+	})
+}
+
+// GetPendingMigrations lists migrations the database has not applied yet, without applying them
+func (h *AdminHandler) GetPendingMigrations(c *gin.Context) {
+	if h.migrations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Migration planning is not available"})
+		return
+	}
+
+	pending, err := h.migrations(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to plan migrations: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pending": pending,
+		"count":   len(pending),
 	})
 }
 
@@ -172,8 +266,8 @@ func (h *AdminHandler) GetEnvVars(c *gin.Context) {
 // UpdateEnvVars updates the .env file and reloads config in memory
 func (h *AdminHandler) UpdateEnvVars(c *gin.Context) {
 	var payload map[string]string
-	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(bindErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
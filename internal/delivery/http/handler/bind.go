@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/abhay2133/api21/config"
+	"github.com/abhay2133/api21/internal/delivery/http/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// errBodyTooLarge is returned by bindJSON when the body exceeds MAX_JSON_BODY_BYTES
+type errBodyTooLarge struct {
+	limit int64
+}
+
+func (e errBodyTooLarge) Error() string {
+	return fmt.Sprintf("request body exceeds maximum size of %d bytes", e.limit)
+}
+
+// bindJSON rejects oversized and overly nested bodies before binding them into dst. With
+// STRICT_JSON_FIELDS enabled it also rejects fields that dst does not declare, so typos are not
+// silently dropped.
+func bindJSON(c *gin.Context, dst interface{}) error {
+	if c.Request.Body == nil {
+		return c.ShouldBindJSON(dst)
+	}
+
+	limit := maxJSONBodyBytes()
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return errBodyTooLarge{limit: limit}
+		}
+		return err
+	}
+	if err := validation.CheckJSONDepth(body, maxJSONDepth()); err != nil {
+		return err
+	}
+
+	if strictJSONFields() {
+		return decodeStrictJSON(body, dst)
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return c.ShouldBindJSON(dst)
+}
+
+// bindErrorStatus is the client error status for a bindJSON failure
+func bindErrorStatus(err error) int {
+	if errors.As(err, new(errBodyTooLarge)) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// decodeStrictJSON mirrors ShouldBindJSON, including struct validation, but refuses unknown fields
+func decodeStrictJSON(body []byte, dst interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return fmt.Errorf("unexpected field %s", field)
+		}
+		return err
+	}
+	if binding.Validator == nil {
+		return nil
+	}
+	return binding.Validator.ValidateStruct(dst)
+}
+
+func strictJSONFields() bool {
+	if config.AppConfig == nil {
+		return false
+	}
+	config.AppConfig.RLock()
+	defer config.AppConfig.RUnlock()
+	return config.AppConfig.StrictJSONFields
+}
+
+func maxJSONDepth() int {
+	if config.AppConfig == nil {
+		return validation.DefaultMaxJSONDepth
+	}
+	config.AppConfig.RLock()
+	defer config.AppConfig.RUnlock()
+	if config.AppConfig.MaxJSONDepth > 0 {
+		return config.AppConfig.MaxJSONDepth
+	}
+	return validation.DefaultMaxJSONDepth
+}
+
+func maxJSONBodyBytes() int64 {
+	if config.AppConfig == nil {
+		return validation.DefaultMaxJSONBodyBytes
+	}
+	config.AppConfig.RLock()
+	defer config.AppConfig.RUnlock()
+	if config.AppConfig.MaxJSONBodyBytes > 0 {
+		return int64(config.AppConfig.MaxJSONBodyBytes)
+	}
+	return validation.DefaultMaxJSONBodyBytes
+}
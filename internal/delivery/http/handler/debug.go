@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// redactedHeaders are request headers whose values are never echoed back, keyed by canonical name
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+	"X-Auth-Token":        true,
+}
+
+// EchoRequest describes the request as the server received it, for debugging proxies and middleware
+func EchoRequest(c *gin.Context) {
+	headers := make(map[string]string, len(c.Request.Header))
+	for name, values := range c.Request.Header {
+		if redactedHeaders[name] {
+			headers[name] = "[REDACTED]"
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+
+	query := make(map[string]string, len(c.Request.URL.Query()))
+	for name, values := range c.Request.URL.Query() {
+		query[name] = strings.Join(values, ", ")
+	}
+
+	// Prefer the ID assigned by the RequestID middleware, which may differ from a malformed incoming header
+	requestID := c.GetString("requestID")
+	if requestID == "" {
+		requestID = c.GetHeader("X-Request-ID")
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+			"headers":    headers,
+			"query":      query,
+			"client_ip":  c.ClientIP(),
+			"request_id": requestID,
+		},
+	})
+}
@@ -2,61 +2,281 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/abhay2133/api21/config"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
+const defaultHealthCheckTimeout = 2 * time.Second
+
+const (
+	HealthStatusHealthy   = "healthy"
+	HealthStatusDegraded  = "degraded"
+	HealthStatusUnhealthy = "unhealthy"
+)
+
+// HealthCheckFunc probes a single dependency, returning an error when it is unavailable
+type HealthCheckFunc func(ctx context.Context) error
+
+type healthCheck struct {
+	name    string
+	fn      HealthCheckFunc
+	timeout time.Duration
+}
+
+// CheckResult is the outcome of a single dependency check
+type CheckResult struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HealthReport aggregates the results of all registered checks
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// HealthChecker runs registered dependency checks concurrently, each bounded by its own timeout
+type HealthChecker struct {
+	mu     sync.RWMutex
+	checks []healthCheck
+	// defaultTimeout is resolved on every run so a reloaded value applies to the next check
+	defaultTimeout func() time.Duration
+}
+
+func NewHealthChecker(defaultTimeout time.Duration) *HealthChecker {
+	return NewDynamicHealthChecker(func() time.Duration { return defaultTimeout })
+}
+
+// NewDynamicHealthChecker bounds checks without their own timeout by whatever defaultTimeout returns at run time
+func NewDynamicHealthChecker(defaultTimeout func() time.Duration) *HealthChecker {
+	return &HealthChecker{
+		defaultTimeout: defaultTimeout,
+	}
+}
+
+// Register adds a check using the checker's default timeout
+func (hc *HealthChecker) Register(name string, fn HealthCheckFunc) {
+	hc.RegisterWithTimeout(name, fn, 0)
+}
+
+// RegisterWithTimeout adds a check bounded by its own timeout, zero meaning the checker's default
+func (hc *HealthChecker) RegisterWithTimeout(name string, fn HealthCheckFunc, timeout time.Duration) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	hc.checks = append(hc.checks, healthCheck{name: name, fn: fn, timeout: timeout})
+}
+
+// Run executes every check concurrently and derives the aggregate status:
+// healthy when all pass, unhealthy when all fail, degraded otherwise
+func (hc *HealthChecker) Run(ctx context.Context) HealthReport {
+	hc.mu.RLock()
+	checks := make([]healthCheck, len(hc.checks))
+	copy(checks, hc.checks)
+	hc.mu.RUnlock()
+
+	defaultTimeout := hc.defaultTimeout()
+	if defaultTimeout <= 0 {
+		defaultTimeout = defaultHealthCheckTimeout
+	}
+
+	results := make(map[string]CheckResult, len(checks))
+	var resultsMu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check healthCheck) {
+			defer wg.Done()
+			if check.timeout <= 0 {
+				check.timeout = defaultTimeout
+			}
+			result := runCheck(ctx, check)
+
+			resultsMu.Lock()
+			results[check.name] = result
+			resultsMu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.Status == "down" {
+			failed++
+		}
+	}
+
+	status := HealthStatusHealthy
+	if failed > 0 && failed == len(results) {
+		status = HealthStatusUnhealthy
+	} else if failed > 0 {
+		status = HealthStatusDegraded
+	}
+
+	return HealthReport{
+		Status: status,
+		Checks: results,
+	}
+}
+
+func runCheck(parent context.Context, check healthCheck) CheckResult {
+	ctx, cancel := context.WithTimeout(parent, check.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- check.fn(ctx)
+	}()
+
+	// Don't trust checks to honour ctx; stop waiting once the deadline passes
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{
+		Status:    "up",
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = "down"
+		result.Error = err.Error()
+	}
+	return result
+}
+
 type HealthHandler struct {
-	db          *gorm.DB
-	redisClient *redis.Client
+	checker *HealthChecker
+	// ready flips once the startup sequence (migrations, warmup, grace delay) has completed
+	ready atomic.Bool
+	// draining stays set once shutdown begins so a late MarkReady cannot undo it
+	draining atomic.Bool
 }
 
 func NewHealthHandler(db *gorm.DB, redisClient *redis.Client) *HealthHandler {
+	checker := NewDynamicHealthChecker(healthCheckTimeout)
+
+	checker.Register("postgres", func(ctx context.Context) error {
+		if db == nil {
+			return errors.New("not connected")
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+
+	checker.Register("redis", func(ctx context.Context) error {
+		if redisClient == nil {
+			return errors.New("not connected")
+		}
+		return redisClient.Ping(ctx).Err()
+	})
+
 	return &HealthHandler{
-		db:          db,
-		redisClient: redisClient,
+		checker: checker,
+	}
+}
+
+// healthCheckTimeout reads HEALTH_CHECK_TIMEOUT on every run so reloads take effect without a restart
+func healthCheckTimeout() time.Duration {
+	if config.AppConfig == nil {
+		return defaultHealthCheckTimeout
 	}
+	config.AppConfig.RLock()
+	defer config.AppConfig.RUnlock()
+	return config.AppConfig.HealthCheckTimeout
+}
+
+// Checker exposes the underlying HealthChecker so further dependencies can be registered
+func (h *HealthHandler) Checker() *HealthChecker {
+	return h.checker
 }
 
 func (h *HealthHandler) GetHealth(c *gin.Context) {
-	dbStatus := "down"
-	if h.db != nil {
-		sqlDB, err := h.db.DB()
-		if err == nil {
-			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-			err = sqlDB.PingContext(ctx)
-			cancel()
-			if err == nil {
-				dbStatus = "up"
-			}
-		}
+	report := h.checker.Run(c.Request.Context())
+
+	data := gin.H{
+		"status": report.Status,
+		"checks": report.Checks,
+	}
+	// Keep the flat per-dependency fields for existing consumers
+	for name, result := range report.Checks {
+		data[name] = result.Status
 	}
 
-	redisStatus := "down"
-	if h.redisClient != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		_, err := h.redisClient.Ping(ctx).Result()
-		cancel()
-		if err == nil {
-			redisStatus = "up"
-		}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
+
+// MarkReady lets /ready report success
+func (h *HealthHandler) MarkReady() {
+	if h.draining.Load() {
+		return
 	}
+	if !h.ready.Swap(true) {
+		log.Println("[health] instance is ready to receive traffic")
+	}
+}
+
+// MarkNotReady makes /ready fail for good so load balancers drain this instance before shutdown
+func (h *HealthHandler) MarkNotReady() {
+	h.draining.Store(true)
+	if h.ready.Swap(false) {
+		log.Println("[health] instance is draining, reporting not ready")
+	}
+}
+
+// Warmup prepares the instance for traffic, such as priming caches or connection pools
+type Warmup func(ctx context.Context) error
+
+// MarkReadyAfter flips readiness in the background once every warmup step has finished and at
+// least grace has passed since start, the moment the process began starting up. A failed warmup
+// is logged rather than keeping the instance out of rotation for good.
+func (h *HealthHandler) MarkReadyAfter(start time.Time, grace time.Duration, warmups ...Warmup) {
+	if len(warmups) == 0 && time.Since(start) >= grace {
+		h.MarkReady()
+		return
+	}
+	go func() {
+		for _, warmup := range warmups {
+			if err := warmup(context.Background()); err != nil {
+				log.Printf("[health] warning: warmup failed: %v", err)
+			}
+		}
+		time.Sleep(time.Until(start.Add(grace)))
+		h.MarkReady()
+	}()
+}
 
-	status := "ok"
-	if dbStatus == "down" || redisStatus == "down" {
-		status = "degraded"
+// GetReady returns 503 until the startup sequence has completed, for load balancer readiness probes
+func (h *HealthHandler) GetReady(c *gin.Context) {
+	if !h.ready.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"data":    gin.H{"ready": false},
+		})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data": gin.H{
-			"status":   status,
-			"postgres": dbStatus,
-			"redis":    redisStatus,
-		},
+		"data":    gin.H{"ready": true},
 	})
 }
@@ -1,10 +1,13 @@
 package handler_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/abhay2133/api21/internal/delivery/http/handler"
 	"github.com/gin-gonic/gin"
@@ -14,7 +17,7 @@ func TestGetHealth(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
 
-	// Inject nil connections to simulate degraded health check
+	// Inject nil connections to simulate failing health checks
 	healthHandler := handler.NewHealthHandler(nil, nil)
 	r.GET("/api/v1/health", healthHandler.GetHealth)
 
@@ -49,7 +52,188 @@ func TestGetHealth(t *testing.T) {
 		t.Errorf("expected redis to be down, got %v", data["redis"])
 	}
 
-	if data["status"] != "degraded" {
-		t.Errorf("expected status to be degraded, got %v", data["status"])
+	if data["status"] != "unhealthy" {
+		t.Errorf("expected status to be unhealthy, got %v", data["status"])
+	}
+
+	if _, ok := data["checks"].(map[string]interface{}); !ok {
+		t.Errorf("expected structured checks block in response")
+	}
+}
+
+func TestHealthChecker(t *testing.T) {
+	passing := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+	hanging := func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	t.Run("all checks passing", func(t *testing.T) {
+		hc := handler.NewHealthChecker(time.Second)
+		hc.Register("db", passing)
+		hc.Register("cache", passing)
+
+		report := hc.Run(context.Background())
+		if report.Status != handler.HealthStatusHealthy {
+			t.Errorf("expected healthy, got %s", report.Status)
+		}
+		if report.Checks["db"].Status != "up" {
+			t.Errorf("expected db to be up, got %s", report.Checks["db"].Status)
+		}
+	})
+
+	t.Run("one failing check", func(t *testing.T) {
+		hc := handler.NewHealthChecker(time.Second)
+		hc.Register("db", passing)
+		hc.Register("cache", failing)
+
+		report := hc.Run(context.Background())
+		if report.Status != handler.HealthStatusDegraded {
+			t.Errorf("expected degraded, got %s", report.Status)
+		}
+		if report.Checks["cache"].Error != "connection refused" {
+			t.Errorf("expected cache error to be reported, got %q", report.Checks["cache"].Error)
+		}
+	})
+
+	t.Run("timing out check", func(t *testing.T) {
+		hc := handler.NewHealthChecker(time.Second)
+		hc.Register("db", passing)
+		hc.RegisterWithTimeout("webhook", hanging, 50*time.Millisecond)
+
+		start := time.Now()
+		report := hc.Run(context.Background())
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("expected run to return near the check timeout, took %s", elapsed)
+		}
+		if report.Checks["webhook"].Status != "down" {
+			t.Errorf("expected timed out check to be down, got %s", report.Checks["webhook"].Status)
+		}
+		if report.Status != handler.HealthStatusDegraded {
+			t.Errorf("expected degraded, got %s", report.Status)
+		}
+	})
+
+	t.Run("default timeout resolved per run", func(t *testing.T) {
+		timeout := time.Second
+		hc := handler.NewDynamicHealthChecker(func() time.Duration { return timeout })
+		hc.Register("webhook", hanging)
+
+		// Shrinking the timeout after registration must still bound the next run
+		timeout = 50 * time.Millisecond
+		start := time.Now()
+		report := hc.Run(context.Background())
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("expected run to use the updated timeout, took %s", elapsed)
+		}
+		if report.Status != handler.HealthStatusUnhealthy {
+			t.Errorf("expected unhealthy, got %s", report.Status)
+		}
+	})
+}
+
+func TestGetReady(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	healthHandler := handler.NewHealthHandler(nil, nil)
+	r.GET("/api/v1/ready", healthHandler.GetReady)
+
+	status := func() int {
+		req, _ := http.NewRequest("GET", "/api/v1/ready", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := status(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before startup completes, got %d", code)
+	}
+
+	healthHandler.MarkReadyAfter(time.Now(), 50*time.Millisecond)
+	if code := status(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 during the grace period, got %d", code)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for status() != http.StatusOK {
+		if time.Now().After(deadline) {
+			t.Fatal("expected readiness to flip after the grace period")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMarkReadyAfter_GraceFromStartAndWarmup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("grace already spent during startup", func(t *testing.T) {
+		healthHandler := handler.NewHealthHandler(nil, nil)
+		r := gin.New()
+		r.GET("/api/v1/ready", healthHandler.GetReady)
+
+		// Startup took longer than the grace period, so no further delay is added
+		healthHandler.MarkReadyAfter(time.Now().Add(-time.Minute), 30*time.Second)
+
+		req, _ := http.NewRequest("GET", "/api/v1/ready", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("expected ready once the grace measured from start has passed, got %d", w.Code)
+		}
+	})
+
+	t.Run("waits for warmup", func(t *testing.T) {
+		healthHandler := handler.NewHealthHandler(nil, nil)
+		r := gin.New()
+		r.GET("/api/v1/ready", healthHandler.GetReady)
+		status := func() int {
+			req, _ := http.NewRequest("GET", "/api/v1/ready", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		release := make(chan struct{})
+		healthHandler.MarkReadyAfter(time.Now(), 0, func(ctx context.Context) error {
+			<-release
+			return nil
+		})
+
+		time.Sleep(50 * time.Millisecond)
+		if code := status(); code != http.StatusServiceUnavailable {
+			t.Fatalf("expected 503 while warmup is running, got %d", code)
+		}
+
+		close(release)
+		deadline := time.Now().Add(time.Second)
+		for status() != http.StatusOK {
+			if time.Now().After(deadline) {
+				t.Fatal("expected readiness to flip once warmup finished")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
+func TestMarkNotReady(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	healthHandler := handler.NewHealthHandler(nil, nil)
+	r.GET("/api/v1/ready", healthHandler.GetReady)
+
+	healthHandler.MarkReady()
+	healthHandler.MarkNotReady()
+	// A grace timer firing after shutdown began must not flip readiness back on
+	healthHandler.MarkReady()
+
+	req, _ := http.NewRequest("GET", "/api/v1/ready", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 while draining, got %d", w.Code)
 	}
 }
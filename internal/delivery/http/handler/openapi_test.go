@@ -0,0 +1,53 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abhay2133/api21/internal/delivery/http/handler"
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetOpenAPISpec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/v1/openapi.json", handler.GetOpenAPISpec)
+
+	req, _ := http.NewRequest("GET", "/api/v1/openapi.json", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("openapi document is not valid JSON: %v", err)
+	}
+
+	if spec["openapi"] != "3.0.3" {
+		t.Errorf("expected openapi version 3.0.3, got %v", spec["openapi"])
+	}
+
+	paths, ok := spec["paths"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("invalid paths block in document")
+	}
+
+	for _, p := range []string{"/health", "/users", "/users/{id}"} {
+		if _, exists := paths[p]; !exists {
+			t.Errorf("expected path %s to be documented", p)
+		}
+	}
+
+	// API_STRING_IDS switches ids to strings, so both forms must be documented
+	components, _ := spec["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	userID, _ := schemas["UserID"].(map[string]interface{})
+	if variants, _ := userID["oneOf"].([]interface{}); len(variants) != 2 {
+		t.Errorf("expected UserID to document integer and string forms, got %v", userID)
+	}
+}
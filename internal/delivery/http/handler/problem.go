@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const problemContentType = "application/problem+json"
+
+// writeClientError sends a 4xx response as an RFC 7807 problem document when the client accepts
+// application/problem+json, and as the usual {"error": ...} envelope otherwise. Extra members of
+// body (such as field errors) are carried over as problem extension members.
+func writeClientError(c *gin.Context, status int, body gin.H) {
+	if !strings.Contains(c.GetHeader("Accept"), problemContentType) {
+		c.JSON(status, body)
+		return
+	}
+
+	problem := gin.H{
+		"type":     "about:blank",
+		"title":    http.StatusText(status),
+		"status":   status,
+		"detail":   body["error"],
+		"instance": c.Request.URL.Path,
+	}
+	for key, value := range body {
+		if key != "error" {
+			problem[key] = value
+		}
+	}
+
+	data, err := json.Marshal(problem)
+	if err != nil {
+		c.JSON(status, body)
+		return
+	}
+	c.Data(status, problemContentType, data)
+}
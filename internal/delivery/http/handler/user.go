@@ -1,23 +1,92 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/abhay2133/api21/internal/delivery/http/validation"
 	"github.com/abhay2133/api21/internal/domain"
 	"github.com/gin-gonic/gin"
 )
 
 type UserHandler struct {
 	userUsecase domain.UserUsecase
+	// stringIDs serializes user IDs as JSON strings so JS clients never round them through float64
+	stringIDs bool
+	// location renders created_at/updated_at in this zone; nil leaves timestamps as stored
+	location *time.Location
 }
 
-func NewUserHandler(uc domain.UserUsecase) *UserHandler {
+func NewUserHandler(uc domain.UserUsecase, stringIDs bool, location *time.Location) *UserHandler {
 	return &UserHandler{
 		userUsecase: uc,
+		stringIDs:   stringIDs,
+		location:    location,
 	}
 }
 
+// stringIDUser shadows the embedded numeric ID with its decimal string form
+type stringIDUser struct {
+	domain.User
+	ID string `json:"id"`
+}
+
+type stringIDImportResult struct {
+	domain.UserImportResult
+	ID string `json:"id,omitempty"`
+}
+
+// localize converts the user's timestamps to the configured response time zone
+func (h *UserHandler) localize(user domain.User) domain.User {
+	if h.location != nil {
+		user.CreatedAt = user.CreatedAt.In(h.location)
+		user.UpdatedAt = user.UpdatedAt.In(h.location)
+	}
+	return user
+}
+
+func (h *UserHandler) presentUser(user *domain.User) interface{} {
+	if !h.stringIDs {
+		return h.localize(*user)
+	}
+	return stringIDUser{User: h.localize(*user), ID: strconv.FormatUint(uint64(user.ID), 10)}
+}
+
+func (h *UserHandler) presentUsers(users []domain.User) interface{} {
+	if !h.stringIDs {
+		if h.location == nil {
+			return users
+		}
+		out := make([]domain.User, len(users))
+		for i, user := range users {
+			out[i] = h.localize(user)
+		}
+		return out
+	}
+	out := make([]stringIDUser, len(users))
+	for i, user := range users {
+		out[i] = stringIDUser{User: h.localize(user), ID: strconv.FormatUint(uint64(user.ID), 10)}
+	}
+	return out
+}
+
+func (h *UserHandler) presentImportResults(results []domain.UserImportResult) interface{} {
+	if !h.stringIDs {
+		return results
+	}
+	out := make([]stringIDImportResult, len(results))
+	for i, result := range results {
+		out[i] = stringIDImportResult{UserImportResult: result}
+		if result.ID != 0 {
+			out[i].ID = strconv.FormatUint(uint64(result.ID), 10)
+		}
+	}
+	return out
+}
+
 func (h *UserHandler) GetUsers(c *gin.Context) {
 	users, err := h.userUsecase.GetUsers(c.Request.Context())
 	if err != nil {
@@ -27,7 +96,7 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    users,
+		"data":    h.presentUsers(users),
 	})
 }
 
@@ -35,42 +104,103 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		writeClientError(c, http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
 		return
 	}
 
 	user, err := h.userUsecase.GetUserByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		writeClientError(c, http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.presentUser(user),
+	})
+}
+
+func (h *UserHandler) GetUserByEmail(c *gin.Context) {
+	user, err := h.userUsecase.GetUserByEmail(c.Request.Context(), c.Param("email"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidEmail) {
+			writeClientError(c, http.StatusBadRequest, gin.H{"error": "Invalid email format"})
+			return
+		}
+		writeClientError(c, http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    user,
+		"data":    h.presentUser(user),
 	})
 }
 
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var input struct {
-		Name  string `json:"name" binding:"required"`
-		Email string `json:"email" binding:"required,email"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+
+	if err := bindJSON(c, &input); err != nil {
+		writeClientError(c, bindErrorStatus(err), gin.H{"error": err.Error()})
+		return
 	}
 
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	errs := validation.New()
+	errs.Required("name", input.Name)
+	errs.Required("email", input.Email)
+	if strings.TrimSpace(input.Email) != "" && domain.ValidateEmail(input.Email) != nil {
+		errs.Add("email", "email must be a valid address")
+	}
+	if errs.HasErrors() {
+		writeClientError(c, http.StatusBadRequest, gin.H{
+			"error":  "Validation failed",
+			"errors": errs,
+		})
 		return
 	}
 
 	user, err := h.userUsecase.CreateUser(c.Request.Context(), input.Name, input.Email)
 	if err != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Failed to create user: " + err.Error()})
+		writeClientError(c, http.StatusConflict, gin.H{"error": "Failed to create user: " + err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
-		"data":    user,
+		"data":    h.presentUser(user),
+	})
+}
+
+func (h *UserHandler) ImportUsers(c *gin.Context) {
+	var rows []domain.UserImportRow
+	if err := bindJSON(c, &rows); err != nil {
+		writeClientError(c, bindErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := h.userUsecase.ImportUsers(c.Request.Context(), rows)
+	if err != nil {
+		writeClientError(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created := 0
+	for _, result := range results {
+		if result.Status == "created" {
+			created++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"created": created,
+			"failed":  len(results) - created,
+			"results": h.presentImportResults(results),
+		},
 	})
 }
 
@@ -78,13 +208,13 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		writeClientError(c, http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
 		return
 	}
 
 	err = h.userUsecase.DeleteUser(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeClientError(c, http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -93,3 +223,24 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		"message": "User deleted successfully",
 	})
 }
+
+// PurgeUsers deletes every user. The router only exposes it outside production.
+func (h *UserHandler) PurgeUsers(c *gin.Context) {
+	if c.Query("confirm") != "true" {
+		writeClientError(c, http.StatusBadRequest, gin.H{"error": "Purging all users requires confirm=true"})
+		return
+	}
+
+	deleted, err := h.userUsecase.PurgeUsers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"deleted": deleted,
+		},
+	})
+}
@@ -0,0 +1,355 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/abhay2133/api21/config"
+	"github.com/abhay2133/api21/internal/delivery/http/handler"
+	"github.com/abhay2133/api21/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateUser_ReportsAllValidationErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	// Validation fails before the usecase is reached, so none is needed
+	userHandler := handler.NewUserHandler(nil, false, nil)
+	r.POST("/api/v1/users", userHandler.CreateUser)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected map[string]string
+	}{
+		{
+			name: "missing name and email",
+			body: `{}`,
+			expected: map[string]string{
+				"name":  "name is required",
+				"email": "email is required",
+			},
+		},
+		{
+			name: "blank name and malformed email",
+			body: `{"name": "  ", "email": "not-an-email"}`,
+			expected: map[string]string{
+				"name":  "name is required",
+				"email": "email must be a valid address",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/api/v1/users", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d", w.Code)
+			}
+
+			var response struct {
+				Error  string            `json:"error"`
+				Errors map[string]string `json:"errors"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to parse JSON response: %v", err)
+			}
+
+			if len(response.Errors) != len(tt.expected) {
+				t.Errorf("expected %d field errors, got %v", len(tt.expected), response.Errors)
+			}
+			for field, msg := range tt.expected {
+				if response.Errors[field] != msg {
+					t.Errorf("expected %s error %q, got %q", field, msg, response.Errors[field])
+				}
+			}
+		})
+	}
+}
+
+// singleUserUsecase returns one fixed user from every lookup
+type singleUserUsecase struct {
+	domain.UserUsecase
+	user domain.User
+}
+
+func (s *singleUserUsecase) CreateUser(ctx context.Context, name, email string) (*domain.User, error) {
+	return &domain.User{ID: 1, Name: name, Email: email}, nil
+}
+
+func (s *singleUserUsecase) GetUserByID(ctx context.Context, id uint) (*domain.User, error) {
+	return &s.user, nil
+}
+
+func (s *singleUserUsecase) GetUsers(ctx context.Context) ([]domain.User, error) {
+	return []domain.User{s.user}, nil
+}
+
+func TestUserHandler_StringIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// Above 2^53, so a float64 round trip would change the value
+	const bigID = uint(9007199254740993)
+	uc := &singleUserUsecase{user: domain.User{ID: bigID, Name: "Alice", Email: "alice@example.com"}}
+
+	tests := []struct {
+		name      string
+		stringIDs bool
+		path      string
+		expected  string
+	}{
+		{name: "numeric by id", stringIDs: false, path: "/api/v1/users/1", expected: `"id":9007199254740993`},
+		{name: "string by id", stringIDs: true, path: "/api/v1/users/1", expected: `"id":"9007199254740993"`},
+		{name: "string list", stringIDs: true, path: "/api/v1/users", expected: `"id":"9007199254740993"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			userHandler := handler.NewUserHandler(uc, tt.stringIDs, nil)
+			r.GET("/api/v1/users", userHandler.GetUsers)
+			r.GET("/api/v1/users/:id", userHandler.GetUserByID)
+
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", w.Code)
+			}
+			if !bytes.Contains(w.Body.Bytes(), []byte(tt.expected)) {
+				t.Errorf("expected body to contain %s, got %s", tt.expected, w.Body.String())
+			}
+			if bytes.Count(w.Body.Bytes(), []byte(`"id"`)) != 1 {
+				t.Errorf("expected a single id field, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateUser_RejectsDeeplyNestedBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	userHandler := handler.NewUserHandler(&singleUserUsecase{}, false, nil)
+	r.POST("/api/v1/users", userHandler.CreateUser)
+
+	nested := `{"name":"Alice","email":"alice@example.com","extra":` + strings.Repeat("[", 100) + strings.Repeat("]", 100) + `}`
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "deeply nested", body: nested, status: http.StatusBadRequest},
+		{name: "brackets inside strings do not count", body: `{"name":"` + strings.Repeat("[", 100) + `","email":"alice@example.com"}`, status: http.StatusCreated},
+		{name: "normal", body: `{"name":"Alice","email":"alice@example.com"}`, status: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/api/v1/users", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUserHandler_ProblemJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	userHandler := handler.NewUserHandler(nil, false, nil)
+	r.POST("/api/v1/users", userHandler.CreateUser)
+	r.GET("/api/v1/users/:id", userHandler.GetUserByID)
+
+	t.Run("validation failure", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/api/v1/users", bytes.NewBufferString(`{"name": "Alice"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/problem+json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d", w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+			t.Errorf("expected problem+json content type, got %q", ct)
+		}
+
+		var problem struct {
+			Type     string            `json:"type"`
+			Title    string            `json:"title"`
+			Status   int               `json:"status"`
+			Detail   string            `json:"detail"`
+			Instance string            `json:"instance"`
+			Errors   map[string]string `json:"errors"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("failed to parse problem response: %v", err)
+		}
+		if problem.Type != "about:blank" || problem.Title != "Bad Request" || problem.Status != http.StatusBadRequest {
+			t.Errorf("unexpected problem type/title/status: %+v", problem)
+		}
+		if problem.Detail != "Validation failed" {
+			t.Errorf("expected detail %q, got %q", "Validation failed", problem.Detail)
+		}
+		if problem.Instance != "/api/v1/users" {
+			t.Errorf("expected instance to be the request path, got %q", problem.Instance)
+		}
+		if problem.Errors["email"] != "email is required" {
+			t.Errorf("expected field errors to be kept, got %v", problem.Errors)
+		}
+	})
+
+	t.Run("default envelope", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/users/abc", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Errorf("expected application/json content type, got %q", ct)
+		}
+		var response map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to parse JSON response: %v", err)
+		}
+		if response["error"] != "Invalid user ID format" {
+			t.Errorf("expected the error envelope, got %v", response)
+		}
+		if _, ok := response["type"]; ok {
+			t.Errorf("expected no problem members without the Accept header, got %v", response)
+		}
+	})
+}
+
+func TestCreateUser_StrictJSONFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	prevConfig := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = prevConfig })
+
+	r := gin.New()
+	userHandler := handler.NewUserHandler(&singleUserUsecase{}, false, nil)
+	r.POST("/api/v1/users", userHandler.CreateUser)
+
+	typo := `{"name":"Alice","email":"alice@example.com","emial":"alice@example.com"}`
+	tests := []struct {
+		name   string
+		strict bool
+		body   string
+		status int
+	}{
+		{name: "unknown field when strict", strict: true, body: typo, status: http.StatusBadRequest},
+		{name: "clean body when strict", strict: true, body: `{"name":"Alice","email":"alice@example.com"}`, status: http.StatusCreated},
+		{name: "unknown field ignored by default", strict: false, body: typo, status: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig = &config.Config{StrictJSONFields: tt.strict}
+
+			req, _ := http.NewRequest("POST", "/api/v1/users", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status == http.StatusBadRequest && !strings.Contains(w.Body.String(), `unexpected field \"emial\"`) {
+				t.Errorf("expected the error to name the unexpected field, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateUser_BodySizeLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	prevConfig := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = prevConfig })
+	config.AppConfig = &config.Config{MaxJSONBodyBytes: 64}
+
+	r := gin.New()
+	userHandler := handler.NewUserHandler(&singleUserUsecase{}, false, nil)
+	r.POST("/api/v1/users", userHandler.CreateUser)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "within limit", body: `{"name":"Alice","email":"alice@example.com"}`, status: http.StatusCreated},
+		{name: "oversized", body: `{"name":"` + strings.Repeat("A", 128) + `","email":"alice@example.com"}`, status: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/api/v1/users", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUserHandler_ResponseTimezone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	stored := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	uc := &singleUserUsecase{user: domain.User{ID: 1, Name: "Alice", Email: "alice@example.com", CreatedAt: stored, UpdatedAt: stored}}
+
+	tests := []struct {
+		name     string
+		zone     string
+		expected string
+	}{
+		{name: "as stored", zone: "", expected: `"created_at":"2026-01-15T12:00:00Z"`},
+		{name: "kolkata", zone: "Asia/Kolkata", expected: `"created_at":"2026-01-15T17:30:00+05:30"`},
+		{name: "new york", zone: "America/New_York", expected: `"created_at":"2026-01-15T07:00:00-05:00"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var loc *time.Location
+			if tt.zone != "" {
+				var err error
+				if loc, err = time.LoadLocation(tt.zone); err != nil {
+					t.Fatalf("failed to load zone: %v", err)
+				}
+			}
+
+			r := gin.New()
+			userHandler := handler.NewUserHandler(uc, false, loc)
+			r.GET("/api/v1/users", userHandler.GetUsers)
+			r.GET("/api/v1/users/:id", userHandler.GetUserByID)
+
+			for _, path := range []string{"/api/v1/users", "/api/v1/users/1"} {
+				req, _ := http.NewRequest("GET", path, nil)
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+
+				if !strings.Contains(w.Body.String(), tt.expected) {
+					t.Errorf("GET %s: expected body to contain %s, got %s", path, tt.expected, w.Body.String())
+				}
+			}
+		})
+	}
+}
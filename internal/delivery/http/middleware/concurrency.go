@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConcurrencyLimit rejects requests with 503 once n are already in flight. A limit of zero or less disables it.
+func ConcurrencyLimit(n int) gin.HandlerFunc {
+	if n <= 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	slots := make(chan struct{}, n)
+	return func(c *gin.Context) {
+		select {
+		case slots <- struct{}{}:
+		default:
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Server is busy, please retry shortly.",
+			})
+			return
+		}
+		// Deferred so the slot is freed even if the handler panics and Recovery takes over
+		defer func() { <-slots }()
+
+		c.Next()
+	}
+}
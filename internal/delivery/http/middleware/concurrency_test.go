@@ -0,0 +1,91 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/abhay2133/api21/internal/delivery/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func TestConcurrencyLimit_RejectsExcessRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const limit = 2
+	started := make(chan struct{}, limit)
+	release := make(chan struct{})
+
+	r := gin.New()
+	r.Use(middleware.ConcurrencyLimit(limit))
+	r.GET("/slow", func(c *gin.Context) {
+		started <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+
+	var wg sync.WaitGroup
+	codes := make([]int, limit)
+	for i := 0; i < limit; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", "/slow", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			codes[i] = w.Code
+		}(i)
+	}
+	for i := 0; i < limit; i++ {
+		<-started
+	}
+
+	// Every slot is held by a slow handler, so the next request is turned away
+	req, _ := http.NewRequest("GET", "/slow", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503 while saturated, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header on 503")
+	}
+
+	close(release)
+	wg.Wait()
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("expected in-flight request %d to succeed, got %d", i, code)
+		}
+	}
+}
+
+func TestConcurrencyLimit_ReleasesSlotOnPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.Use(middleware.ConcurrencyLimit(1))
+	r.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	r.GET("/ok", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/panic", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500 from panicking handler, got %d", w.Code)
+	}
+
+	req, _ = http.NewRequest("GET", "/ok", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected slot to be released after panic, got status %d", w.Code)
+	}
+}
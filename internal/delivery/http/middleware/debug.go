@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/abhay2133/api21/config"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireDebugEndpoints answers 404 unless DEBUG_ENDPOINTS is enabled, so disabled debug routes look like any unknown route
+func RequireDebugEndpoints() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !debugEndpointsEnabled() {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "API route not found",
+				"code":  "NOT_FOUND",
+				"path":  c.Request.URL.Path,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+func debugEndpointsEnabled() bool {
+	if config.AppConfig == nil {
+		return false
+	}
+	config.AppConfig.RLock()
+	defer config.AppConfig.RUnlock()
+	return config.AppConfig.DebugEndpoints
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireNonProduction rejects the request with 403 when running in production
+func RequireNonProduction(env string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if env == "production" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "This endpoint is disabled in production"})
+			return
+		}
+		c.Next()
+	}
+}
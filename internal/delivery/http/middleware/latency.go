@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/abhay2133/api21/services"
+	"github.com/gin-gonic/gin"
+)
+
+// LatencyRecorder records each request's latency under its route pattern. A nil tracker disables recording.
+func LatencyRecorder(tracker *services.LatencyTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tracker == nil {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		// Group by pattern (/users/:id) rather than raw path so the table stays bounded. Unmatched
+		// requests share one key whatever their method, since clients can send arbitrary method tokens.
+		route := c.FullPath()
+		if route == "" {
+			tracker.Record("unmatched", time.Since(start))
+			return
+		}
+		tracker.Record(c.Request.Method+" "+route, time.Since(start))
+	}
+}
@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abhay2133/api21/internal/delivery/http/middleware"
+	"github.com/abhay2133/api21/services"
+	"github.com/gin-gonic/gin"
+)
+
+func TestLatencyRecorder_GroupsByRoutePattern(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tracker := services.NewLatencyTracker()
+
+	r := gin.New()
+	r.Use(middleware.LatencyRecorder(tracker))
+	r.GET("/items/:id", func(c *gin.Context) {
+		time.Sleep(5 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/items/1", "/items/2", "/items/3", "/missing"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	counts := map[string]services.RouteLatency{}
+	for _, stats := range tracker.Snapshot() {
+		counts[stats.Route] = stats
+	}
+
+	items, ok := counts["GET /items/:id"]
+	if !ok || items.Count != 3 {
+		t.Fatalf("expected 3 requests under GET /items/:id, got %+v", counts)
+	}
+	if items.MinMs < 5 || items.P95Ms < items.MinMs || items.P95Ms > items.MaxMs {
+		t.Errorf("expected min >= 5ms and min <= p95 <= max, got %+v", items)
+	}
+	if counts["unmatched"].Count != 1 {
+		t.Errorf("expected unknown paths grouped as unmatched, got %+v", counts)
+	}
+}
+
+func TestLatencyRecorder_UnmatchedMethodsShareOneRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tracker := services.NewLatencyTracker()
+
+	r := gin.New()
+	r.Use(middleware.LatencyRecorder(tracker))
+	r.GET("/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 50; i++ {
+		req, _ := http.NewRequest(fmt.Sprintf("FOO%d", i), "/x", nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	routes := tracker.Snapshot()
+	if len(routes) != 1 || routes[0].Route != "unmatched" || routes[0].Count != 50 {
+		t.Errorf("expected arbitrary methods to share the unmatched route, got %+v", routes)
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/abhay2133/api21/config"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultMaxPathLength   = 2048
+	defaultMaxPathSegments = 32
+)
+
+// PathGuard rejects over-long (414) or deeply nested (400) paths before any routing work,
+// using MAX_PATH_LENGTH and MAX_PATH_SEGMENTS when configured
+func PathGuard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		maxLength, maxSegments := pathLimits()
+		path := c.Request.URL.EscapedPath()
+
+		if len(path) > maxLength {
+			c.AbortWithStatusJSON(http.StatusRequestURITooLong, gin.H{
+				"error": "Request path is too long",
+			})
+			return
+		}
+
+		if strings.Count(path, "/") > maxSegments {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "Request path has too many segments",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func pathLimits() (int, int) {
+	maxLength, maxSegments := defaultMaxPathLength, defaultMaxPathSegments
+	if config.AppConfig == nil {
+		return maxLength, maxSegments
+	}
+
+	config.AppConfig.RLock()
+	defer config.AppConfig.RUnlock()
+	if config.AppConfig.MaxPathLength > 0 {
+		maxLength = config.AppConfig.MaxPathLength
+	}
+	if config.AppConfig.MaxPathSegments > 0 {
+		maxSegments = config.AppConfig.MaxPathSegments
+	}
+	return maxLength, maxSegments
+}
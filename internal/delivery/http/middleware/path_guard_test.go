@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abhay2133/api21/config"
+	"github.com/abhay2133/api21/internal/delivery/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func TestPathGuard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	prevConfig := config.AppConfig
+	config.AppConfig = &config.Config{MaxPathLength: 64, MaxPathSegments: 6}
+	t.Cleanup(func() { config.AppConfig = prevConfig })
+
+	r := gin.New()
+	r.Use(middleware.PathGuard())
+	r.NoRoute(func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{name: "normal path", path: "/api/v1/users/by-email/alice@example.com", status: http.StatusOK},
+		{name: "over-length path", path: "/api/v1/users/by-email/" + strings.Repeat("a", 64), status: http.StatusRequestURITooLong},
+		{name: "too many segments", path: "/a/b/c/d/e/f/g", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("GET %s: expected status %d, got %d", tt.path, tt.status, w.Code)
+			}
+		})
+	}
+}
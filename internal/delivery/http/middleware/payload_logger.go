@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/abhay2133/api21/config"
+	"github.com/gin-gonic/gin"
+)
+
+// maxLoggedBodyBytes caps how much of each request and response body is written to the log
+const maxLoggedBodyBytes = 4096
+
+// sensitiveFields are JSON keys whose values are never logged, compared after normalizeFieldName
+var sensitiveFields = map[string]bool{
+	"password":      true,
+	"apikey":        true,
+	"token":         true,
+	"accesstoken":   true,
+	"refreshtoken":  true,
+	"secret":        true,
+	"authorization": true,
+}
+
+// bodyCaptureWriter tees up to maxLoggedBodyBytes of the response body into a buffer
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	if room := maxLoggedBodyBytes + 1 - w.body.Len(); room > 0 {
+		if len(b) < room {
+			room = len(b)
+		}
+		w.body.Write(b[:room])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// PayloadLogger logs request and response bodies with sensitive fields redacted while DEBUG_LOG_BODIES is enabled
+func PayloadLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !payloadLoggingEnabled() {
+			c.Next()
+			return
+		}
+
+		var reqBody []byte
+		if c.Request.Body != nil {
+			// Only peek at what can be logged; one extra byte tells formatLoggedBody the body was longer
+			var err error
+			reqBody, err = io.ReadAll(io.LimitReader(c.Request.Body, maxLoggedBodyBytes+1))
+			if err != nil {
+				log.Printf("[payload] failed to read request body: %v", err)
+			}
+			// Put the peeked prefix back in front of the unread rest so the handler sees the whole body
+			c.Request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(reqBody), c.Request.Body), c.Request.Body}
+		}
+
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		log.Printf("[payload] %s %s | request: %s | response (%d): %s",
+			c.Request.Method,
+			c.Request.URL.Path,
+			formatLoggedBody(reqBody),
+			c.Writer.Status(),
+			formatLoggedBody(writer.body.Bytes()),
+		)
+	}
+}
+
+func payloadLoggingEnabled() bool {
+	if config.AppConfig == nil {
+		return false
+	}
+	config.AppConfig.RLock()
+	defer config.AppConfig.RUnlock()
+	return config.AppConfig.DebugLogBodies
+}
+
+// formatLoggedBody redacts sensitive JSON fields and truncates the result to maxLoggedBodyBytes
+func formatLoggedBody(body []byte) string {
+	if len(body) == 0 {
+		return "<empty>"
+	}
+
+	if looksLikeJSON(body) {
+		var parsed interface{}
+		if len(body) > maxLoggedBodyBytes || json.Unmarshal(body, &parsed) != nil {
+			// JSON that cannot be parsed cannot be redacted, so keep it out of the log entirely
+			return "<json body omitted: too large or malformed to redact>"
+		}
+		if redacted, err := json.Marshal(redactSensitive(parsed)); err == nil {
+			body = redacted
+		}
+	}
+
+	if len(body) > maxLoggedBodyBytes {
+		return string(body[:maxLoggedBodyBytes]) + "...(truncated)"
+	}
+	return string(body)
+}
+
+func looksLikeJSON(body []byte) bool {
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+func redactSensitive(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if sensitiveFields[normalizeFieldName(k)] {
+				val[k] = "[REDACTED]"
+			} else {
+				val[k] = redactSensitive(child)
+			}
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = redactSensitive(child)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// normalizeFieldName folds api_key, api-key and apiKey to the same lookup key
+func normalizeFieldName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, "_", "")
+	return strings.ReplaceAll(name, "-", "")
+}
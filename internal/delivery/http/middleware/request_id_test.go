@@ -0,0 +1,55 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abhay2133/api21/internal/delivery/http/middleware"
+	"github.com/abhay2133/api21/internal/infrastructure/database"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		incoming string
+		reused   bool
+	}{
+		{name: "reuses incoming ID", incoming: "req-123", reused: true},
+		{name: "generates when missing", incoming: ""},
+		{name: "replaces malformed ID", incoming: "bad id */"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ctxID string
+			r := gin.New()
+			r.Use(middleware.RequestID())
+			r.GET("/", func(c *gin.Context) {
+				ctxID, _ = database.RequestIDFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest("GET", "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set("X-Request-ID", tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			got := w.Header().Get("X-Request-ID")
+			if got == "" {
+				t.Fatal("expected a request ID on the response")
+			}
+			if (got == tt.incoming) != tt.reused {
+				t.Errorf("incoming %q: unexpected response ID %q", tt.incoming, got)
+			}
+			if ctxID != got {
+				t.Errorf("expected request context to carry %q, got %q", got, ctxID)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abhay2133/api21/config"
+	"github.com/abhay2133/api21/internal/delivery/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func newSecureHeadersRouter(t *testing.T, cfg *config.Config) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	prevConfig := config.AppConfig
+	config.AppConfig = cfg
+	t.Cleanup(func() { config.AppConfig = prevConfig })
+
+	page := filepath.Join(t.TempDir(), "index.html")
+	if err := os.WriteFile(page, []byte("<html><body>docs</body></html>"), 0o644); err != nil {
+		t.Fatalf("failed to write static page: %v", err)
+	}
+
+	r := gin.New()
+	r.Use(middleware.SecureHeaders())
+	r.StaticFile("/", page)
+	r.GET("/api/v1/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	})
+	return r
+}
+
+func TestSecureHeaders_Defaults(t *testing.T) {
+	r := newSecureHeadersRouter(t, nil)
+
+	for _, path := range []string{"/api/v1/health", "/"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: expected status 200, got %d", path, w.Code)
+		}
+
+		want := map[string]string{
+			"X-Content-Type-Options":  "nosniff",
+			"X-Frame-Options":         "DENY",
+			"Referrer-Policy":         middleware.DefaultReferrerPolicy,
+			"Content-Security-Policy": middleware.DefaultContentSecurityPolicy,
+		}
+		for header, value := range want {
+			if got := w.Header().Get(header); got != value {
+				t.Errorf("GET %s: expected %s %q, got %q", path, header, value, got)
+			}
+		}
+		if hsts := w.Header().Get("Strict-Transport-Security"); hsts != "" {
+			t.Errorf("GET %s: expected no HSTS outside production, got %q", path, hsts)
+		}
+	}
+}
+
+func TestSecureHeaders_Overrides(t *testing.T) {
+	r := newSecureHeadersRouter(t, &config.Config{
+		Env:                   "production",
+		ContentSecurityPolicy: "default-src 'none'",
+		ReferrerPolicy:        "no-referrer",
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v1/health", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Security-Policy"); got != "default-src 'none'" {
+		t.Errorf("expected overridden CSP, got %q", got)
+	}
+	if got := w.Header().Get("Referrer-Policy"); got != "no-referrer" {
+		t.Errorf("expected overridden Referrer-Policy, got %q", got)
+	}
+	if got := w.Header().Get("Strict-Transport-Security"); got == "" {
+		t.Error("expected HSTS in production")
+	}
+}
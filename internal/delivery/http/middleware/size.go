@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"io"
+
+	"github.com/abhay2133/api21/services"
+	"github.com/gin-gonic/gin"
+)
+
+// countingBody counts the request body bytes the handler actually reads
+type countingBody struct {
+	io.ReadCloser
+	n int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// SizeRecorder records each request and response body size. A nil histogram disables recording.
+func SizeRecorder(histogram *services.SizeHistogram) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if histogram == nil {
+			c.Next()
+			return
+		}
+
+		var body *countingBody
+		if c.Request.Body != nil {
+			body = &countingBody{ReadCloser: c.Request.Body}
+			c.Request.Body = body
+		}
+
+		c.Next()
+
+		// Chunked requests have no Content-Length, so fall back to what was read
+		requestBytes := c.Request.ContentLength
+		if requestBytes < 0 {
+			requestBytes = 0
+			if body != nil {
+				requestBytes = body.n
+			}
+		}
+
+		responseBytes := int64(c.Writer.Size())
+		if responseBytes < 0 {
+			responseBytes = 0
+		}
+		histogram.Record(requestBytes, responseBytes)
+	}
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abhay2133/api21/internal/delivery/http/middleware"
+	"github.com/abhay2133/api21/services"
+	"github.com/gin-gonic/gin"
+)
+
+func TestSizeRecorder_BucketsBodySizes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	histogram := services.NewSizeHistogram([]int64{100, 1000})
+
+	r := gin.New()
+	r.Use(middleware.SizeRecorder(histogram))
+	r.POST("/echo", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusOK, "text/plain", body)
+	})
+
+	for _, size := range []int{10, 50, 500, 5000} {
+		req, _ := http.NewRequest("POST", "/echo", strings.NewReader(strings.Repeat("x", size)))
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Chunked upload: no Content-Length, so the bytes read are counted instead
+	req, _ := http.NewRequest("POST", "/echo", io.NopCloser(strings.NewReader(strings.Repeat("x", 700))))
+	req.ContentLength = -1
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	snap := histogram.Snapshot()
+	want := []int64{2, 2, 1}
+	for _, dist := range []services.SizeDistribution{snap.Request, snap.Response} {
+		for i, bucket := range dist.Buckets {
+			if bucket.Count != want[i] {
+				t.Errorf("bucket le=%s: expected %d, got %d", bucket.Le, want[i], bucket.Count)
+			}
+		}
+		if dist.TotalBytes != 6260 {
+			t.Errorf("expected 6260 total bytes, got %d", dist.TotalBytes)
+		}
+	}
+}
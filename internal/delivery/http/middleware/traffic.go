@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/abhay2133/api21/services"
+	"github.com/gin-gonic/gin"
+)
+
+// TrafficRecorder counts every request against its client IP. A nil counter disables recording.
+func TrafficRecorder(counter *services.TrafficCounter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if counter != nil {
+			counter.Record(c.ClientIP())
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+
+	"github.com/abhay2133/api21/internal/infrastructure/database"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TxKey is the gin context key holding the request's *gorm.DB transaction
+const TxKey = "tx"
+
+// bufferedResponseWriter holds the headers, status and body back until the transaction outcome is
+// known, so a failed commit can answer without any of the handler's success response leaking out.
+type bufferedResponseWriter struct {
+	gin.ResponseWriter
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponseWriter(w gin.ResponseWriter) *bufferedResponseWriter {
+	// Start from the headers earlier middleware already set so the handler still sees them
+	return &bufferedResponseWriter{ResponseWriter: w, header: w.Header().Clone()}
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) WriteHeader(code int) {
+	if code > 0 && w.body.Len() == 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedResponseWriter) WriteHeaderNow() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	w.WriteHeaderNow()
+	return w.body.Write(b)
+}
+
+func (w *bufferedResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *bufferedResponseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *bufferedResponseWriter) Size() int {
+	if w.status == 0 {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedResponseWriter) Written() bool {
+	return w.status != 0
+}
+
+// Flush is a no-op: nothing may reach the client before the commit
+func (w *bufferedResponseWriter) Flush() {}
+
+// flush sends the held response through the underlying writer
+func (w *bufferedResponseWriter) flush() {
+	if w.status == 0 {
+		return
+	}
+	header := w.ResponseWriter.Header()
+	for key := range header {
+		if _, kept := w.header[key]; !kept {
+			header.Del(key)
+		}
+	}
+	for key, values := range w.header {
+		header[key] = values
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if _, err := w.ResponseWriter.Write(w.body.Bytes()); err != nil {
+		log.Printf("[tx] failed to write buffered response: %v", err)
+	}
+}
+
+// Transaction runs the request inside one database transaction that repositories join through the
+// request context. It commits when the handler responds 2xx and rolls back on any other status or a
+// panic. The response is held back until the commit succeeds, so a failed commit becomes a 500
+// instead of a success the client has already seen.
+func Transaction(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if db == nil {
+			c.Next()
+			return
+		}
+
+		tx := db.WithContext(c.Request.Context()).Begin()
+		if tx.Error != nil {
+			log.Printf("[tx] failed to begin transaction: %v", tx.Error)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to start database transaction"})
+			return
+		}
+
+		c.Set(TxKey, tx)
+		c.Request = c.Request.WithContext(database.ContextWithTx(c.Request.Context(), tx))
+
+		original := c.Writer
+		writer := newBufferedResponseWriter(original)
+		c.Writer = writer
+
+		committed := false
+		// Deferred so a panicking handler still releases the transaction and lets Recovery write to the client
+		defer func() {
+			c.Writer = original
+			if !committed {
+				if err := tx.Rollback().Error; err != nil {
+					log.Printf("[tx] rollback failed for %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+				}
+			}
+		}()
+
+		c.Next()
+
+		status := writer.Status()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices || len(c.Errors) > 0 {
+			writer.flush()
+			return
+		}
+
+		committed = true
+		if err := tx.Commit().Error; err != nil {
+			log.Printf("[tx] commit failed for %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+			c.Writer = original
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit database transaction"})
+			return
+		}
+		writer.flush()
+	}
+}
@@ -0,0 +1,153 @@
+package middleware_test
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abhay2133/api21/internal/delivery/http/middleware"
+	"github.com/abhay2133/api21/internal/infrastructure/database"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	_ "modernc.org/sqlite"
+)
+
+func newTxTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	sqlDB, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	// A single connection keeps the in-memory database alive across queries
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	// Statements below use no placeholders, so the postgres dialector runs them on sqlite unchanged
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open gorm: %v", err)
+	}
+	if err := db.Exec("CREATE TABLE items (name TEXT)").Error; err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	return db
+}
+
+func countItems(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Raw("SELECT COUNT(*) FROM items").Scan(&count).Error; err != nil {
+		t.Fatalf("failed to count items: %v", err)
+	}
+	return count
+}
+
+func TestTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		after      func(c *gin.Context)
+		want       int64
+		wantStatus int
+	}{
+		{
+			name: "commits on success",
+			after: func(c *gin.Context) {
+				c.Header("Location", "/items/1")
+				c.JSON(http.StatusCreated, gin.H{"success": true})
+			},
+			want:       2,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "rolls back on error status",
+			after:      func(c *gin.Context) { c.JSON(http.StatusConflict, gin.H{"error": "boom"}) },
+			want:       0,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "rolls back on panic",
+			after:      func(c *gin.Context) { panic("boom") },
+			want:       0,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTxTestDB(t)
+
+			r := gin.New()
+			r.Use(gin.Recovery())
+			r.Use(middleware.Transaction(db))
+			r.POST("/items", func(c *gin.Context) {
+				tx, ok := database.TxFromContext(c.Request.Context())
+				if !ok {
+					t.Fatal("expected the request context to carry a transaction")
+				}
+				tx.Exec("INSERT INTO items (name) VALUES ('first')")
+				tx.Exec("INSERT INTO items (name) VALUES ('second')")
+				tt.after(c)
+			})
+
+			req, _ := http.NewRequest("POST", "/items", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusCreated && w.Header().Get("Location") != "/items/1" {
+				t.Errorf("expected the committed response's headers to be sent, got %v", w.Header())
+			}
+			if got := countItems(t, db); got != tt.want {
+				t.Errorf("expected %d committed items, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTransaction_CommitFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newTxTestDB(t)
+
+	// A deferred foreign key is only checked at COMMIT, which makes the commit itself fail
+	for _, stmt := range []string{
+		"PRAGMA foreign_keys = ON",
+		"CREATE TABLE parents (id INTEGER PRIMARY KEY)",
+		"CREATE TABLE children (parent_id INTEGER REFERENCES parents(id) DEFERRABLE INITIALLY DEFERRED)",
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("failed to prepare schema: %v", err)
+		}
+	}
+
+	r := gin.New()
+	r.Use(middleware.Transaction(db))
+	r.POST("/children", func(c *gin.Context) {
+		tx, _ := database.TxFromContext(c.Request.Context())
+		if err := tx.Exec("INSERT INTO children (parent_id) VALUES (42)").Error; err != nil {
+			t.Fatalf("insert failed before commit: %v", err)
+		}
+		c.Header("Location", "/children/1")
+		c.JSON(http.StatusCreated, gin.H{"success": true})
+	})
+
+	req, _ := http.NewRequest("POST", "/children", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 when the commit fails, got %d", w.Code)
+	}
+	if strings.Contains(w.Body.String(), "success") {
+		t.Errorf("expected the handler's response to be discarded, got %s", w.Body.String())
+	}
+	if location := w.Header().Get("Location"); location != "" {
+		t.Errorf("expected the handler's headers to be discarded, got Location %q", location)
+	}
+}
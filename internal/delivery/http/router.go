@@ -2,11 +2,13 @@ package http
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/abhay2133/api21/config"
 	"github.com/abhay2133/api21/internal/delivery/http/handler"
 	"github.com/abhay2133/api21/internal/delivery/http/middleware"
 	"github.com/abhay2133/api21/internal/domain"
+	"github.com/abhay2133/api21/services"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
@@ -14,12 +16,16 @@ import (
 
 func NewRouter(
 	env string,
+	maxConcurrentRequests int,
 	dbConn *gorm.DB,
 	redisClient *redis.Client,
 	userHandler *handler.UserHandler,
 	healthHandler *handler.HealthHandler,
 	adminHandler *handler.AdminHandler,
 	sessionUsecase domain.SessionUsecase,
+	trafficCounter *services.TrafficCounter,
+	latencyTracker *services.LatencyTracker,
+	sizeHistogram *services.SizeHistogram,
 ) *gin.Engine {
 	if env == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -29,7 +35,15 @@ func NewRouter(
 
 	// Global Middlewares
 	r.Use(gin.Recovery())
+	r.Use(middleware.RequestID())
+	r.Use(middleware.SecureHeaders())
+	r.Use(middleware.ConcurrencyLimit(maxConcurrentRequests))
 	r.Use(middleware.Logger())
+	r.Use(middleware.PathGuard())
+	r.Use(middleware.TrafficRecorder(trafficCounter))
+	r.Use(middleware.LatencyRecorder(latencyTracker))
+	r.Use(middleware.SizeRecorder(sizeHistogram))
+	r.Use(middleware.PayloadLogger())
 	r.Use(middleware.ForceSSL(env))
 	r.Use(middleware.RateLimiter(redisClient))
 
@@ -41,12 +55,18 @@ func NewRouter(
 	api := r.Group("/api/v1")
 	{
 		api.GET("/health", healthHandler.GetHealth)
+		api.GET("/ready", healthHandler.GetReady)
+		api.GET("/openapi.json", handler.GetOpenAPISpec)
+		api.Any("/debug/echo", middleware.RequireDebugEndpoints(), handler.EchoRequest)
 
 		// User endpoints
 		api.GET("/users", userHandler.GetUsers)
 		api.GET("/users/:id", userHandler.GetUserByID)
+		api.GET("/users/by-email/:email", userHandler.GetUserByEmail)
 		api.POST("/users", userHandler.CreateUser)
+		api.POST("/users/import", middleware.Transaction(dbConn), userHandler.ImportUsers)
 		api.DELETE("/users/:id", userHandler.DeleteUser)
+		api.DELETE("/users", middleware.RequireNonProduction(env), middleware.AdminAuth(sessionUsecase), middleware.Transaction(dbConn), userHandler.PurgeUsers)
 	}
 
 	// Custom CORS middleware for API
@@ -97,6 +117,10 @@ func NewRouter(
 	protectedAdmin.Use(middleware.AdminAuth(sessionUsecase))
 	{
 		protectedAdmin.GET("/metrics", adminHandler.GetSystemMetrics)
+		protectedAdmin.GET("/ping/history", adminHandler.GetPingHistory)
+		protectedAdmin.GET("/traffic/top-ips", adminHandler.GetTopIPs)
+		protectedAdmin.GET("/latency", adminHandler.GetLatency)
+		protectedAdmin.GET("/migrations/pending", adminHandler.GetPendingMigrations)
 		protectedAdmin.GET("/env", adminHandler.GetEnvVars)
 		protectedAdmin.POST("/env", adminHandler.UpdateEnvVars)
 		protectedAdmin.GET("/sessions", adminHandler.GetSessions)
@@ -110,12 +134,16 @@ func NewRouter(
 		if len(path) >= 5 && path[:5] == "/api/" {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error": "API route not found",
+				"code":  "NOT_FOUND",
+				"path":  path,
 			})
 			return
 		}
 		if len(path) >= 7 && path[:7] == "/admin/" {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error": "Admin API route not found",
+				"code":  "NOT_FOUND",
+				"path":  path,
 			})
 			return
 		}
@@ -123,6 +151,25 @@ func NewRouter(
 		c.File("./static/index.html")
 	})
 
+	// Reply 405 with an Allow header (set by gin) instead of 404 when only the method is wrong
+	r.HandleMethodNotAllowed = true
+	r.NoMethod(func(c *gin.Context) {
+		path := c.Request.URL.Path
+		isAPI := len(path) >= 5 && path[:5] == "/api/"
+		isAdmin := len(path) >= 7 && path[:7] == "/admin/"
+		if !isAPI && !isAdmin {
+			// Non-API paths fall back to the docs, as they do in NoRoute
+			c.File("./static/index.html")
+			return
+		}
+
+		allowed := strings.Split(c.Writer.Header().Get("Allow"), ", ")
+		c.JSON(http.StatusMethodNotAllowed, gin.H{
+			"error":           "Method " + c.Request.Method + " not allowed",
+			"allowed_methods": allowed,
+		})
+	})
+
 	return r
 }
 
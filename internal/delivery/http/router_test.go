@@ -0,0 +1,346 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abhay2133/api21/config"
+	deliveryHttp "github.com/abhay2133/api21/internal/delivery/http"
+	"github.com/abhay2133/api21/internal/delivery/http/handler"
+	"github.com/abhay2133/api21/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// stubUserUsecase serves a single fixed user so routing can be exercised without a database
+type stubUserUsecase struct {
+	user   domain.User
+	purged bool
+}
+
+func (s *stubUserUsecase) CreateUser(ctx context.Context, name, email string) (*domain.User, error) {
+	return &domain.User{Name: name, Email: email}, nil
+}
+
+func (s *stubUserUsecase) ImportUsers(ctx context.Context, rows []domain.UserImportRow) ([]domain.UserImportResult, error) {
+	return nil, nil
+}
+
+func (s *stubUserUsecase) GetUsers(ctx context.Context) ([]domain.User, error) {
+	return []domain.User{s.user}, nil
+}
+
+func (s *stubUserUsecase) GetUserByID(ctx context.Context, id uint) (*domain.User, error) {
+	if id != s.user.ID {
+		return nil, errors.New("user not found")
+	}
+	return &s.user, nil
+}
+
+func (s *stubUserUsecase) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	if email != s.user.Email {
+		return nil, errors.New("user not found")
+	}
+	return &s.user, nil
+}
+
+func (s *stubUserUsecase) DeleteUser(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (s *stubUserUsecase) PurgeUsers(ctx context.Context) (int64, error) {
+	s.purged = true
+	return 1, nil
+}
+
+// stubSessionUsecase accepts only the token "valid-token"
+type stubSessionUsecase struct{}
+
+func (s *stubSessionUsecase) CreateSession(ctx context.Context, username, ip, ua string, deactivateOthers bool) (*domain.Session, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubSessionUsecase) ValidateToken(ctx context.Context, token string, currentIP string, currentUA string) (*domain.Session, error) {
+	if token != "valid-token" {
+		return nil, errors.New("invalid token")
+	}
+	return &domain.Session{Username: "admin", Token: token}, nil
+}
+
+func (s *stubSessionUsecase) GetActiveSessions(ctx context.Context, username string) ([]domain.Session, error) {
+	return nil, nil
+}
+
+func (s *stubSessionUsecase) RevokeSession(ctx context.Context, token string) error {
+	return nil
+}
+
+func (s *stubSessionUsecase) RevokeSessionByID(ctx context.Context, id uint, username string) error {
+	return nil
+}
+
+func newTestRouter() *gin.Engine {
+	r, _ := newTestRouterForEnv("test")
+	return r
+}
+
+func newTestRouterForEnv(env string) (*gin.Engine, *stubUserUsecase) {
+	gin.SetMode(gin.TestMode)
+	userUsecase := &stubUserUsecase{
+		user: domain.User{ID: 1, Name: "Alice", Email: "alice.smith@example.com"},
+	}
+	return deliveryHttp.NewRouter(
+		env,
+		0,
+		nil,
+		nil,
+		handler.NewUserHandler(userUsecase, false, nil),
+		handler.NewHealthHandler(nil, nil),
+		handler.NewAdminHandler(nil, nil, nil, nil, nil, nil),
+		&stubSessionUsecase{},
+		nil,
+		nil,
+		nil,
+	), userUsecase
+}
+
+func TestRouter_MethodNotAllowed(t *testing.T) {
+	r := newTestRouter()
+
+	req, _ := http.NewRequest("PATCH", "/api/v1/users", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status 405, got %d", w.Code)
+	}
+
+	if allow := w.Header().Get("Allow"); allow != "GET, POST, DELETE" {
+		t.Errorf("expected Allow header %q, got %q", "GET, POST, DELETE", allow)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse JSON response: %v", err)
+	}
+
+	if response["error"] != "Method PATCH not allowed" {
+		t.Errorf("unexpected error message: %v", response["error"])
+	}
+
+	allowed, ok := response["allowed_methods"].([]interface{})
+	if !ok || len(allowed) != 3 {
+		t.Errorf("expected three allowed methods in body, got %v", response["allowed_methods"])
+	}
+}
+
+func TestRouter_UserByEmail(t *testing.T) {
+	r := newTestRouter()
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{name: "email with dots", path: "/api/v1/users/by-email/alice.smith@example.com", status: http.StatusOK},
+		{name: "unknown email", path: "/api/v1/users/by-email/bob@example.com", status: http.StatusNotFound},
+		{name: "numeric id still routed", path: "/api/v1/users/1", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("GET %s: expected status %d, got %d", tt.path, tt.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestRouter_PurgeUsers(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    string
+		path   string
+		token  string
+		status int
+		purged bool
+	}{
+		{name: "confirmed in test env", env: "test", path: "/api/v1/users?confirm=true", token: "valid-token", status: http.StatusOK, purged: true},
+		{name: "missing confirm", env: "test", path: "/api/v1/users", token: "valid-token", status: http.StatusBadRequest},
+		{name: "missing session", env: "test", path: "/api/v1/users?confirm=true", status: http.StatusUnauthorized},
+		{name: "production", env: "production", path: "/api/v1/users?confirm=true", token: "valid-token", status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, userUsecase := newTestRouterForEnv(tt.env)
+
+			req, _ := http.NewRequest("DELETE", tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			// Satisfy ForceSSL when running as production
+			req.Header.Set("X-Forwarded-Proto", "https")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if userUsecase.purged != tt.purged {
+				t.Errorf("expected purged=%v, got %v", tt.purged, userUsecase.purged)
+			}
+		})
+	}
+}
+
+func TestRouter_UnknownRouteJSON(t *testing.T) {
+	r := newTestRouter()
+
+	path := "/api/v1/does-not-exist"
+	req, _ := http.NewRequest("GET", path, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", w.Code)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse JSON response: %v", err)
+	}
+	if response["code"] != "NOT_FOUND" {
+		t.Errorf("expected code NOT_FOUND, got %v", response["code"])
+	}
+	if response["path"] != path {
+		t.Errorf("expected path %q, got %v", path, response["path"])
+	}
+}
+
+func TestRouter_DocsFallback(t *testing.T) {
+	// The docs are served from ./static relative to the repository root
+	t.Chdir("../../..")
+	r := newTestRouter()
+
+	for _, path := range []string{"/", "/guides/getting-started"} {
+		t.Run(path, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", w.Code)
+			}
+			if !strings.Contains(w.Body.String(), "<html") {
+				t.Errorf("expected the docs page, got %q", w.Body.String())
+			}
+		})
+	}
+
+	t.Run("/index.html", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/index.html", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		// net/http canonicalizes index.html to its directory
+		if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "./" {
+			t.Errorf("expected a redirect to the docs root, got %d to %q", w.Code, w.Header().Get("Location"))
+		}
+	})
+}
+
+func TestRouter_MethodNotAllowedServesDocsOutsideAPI(t *testing.T) {
+	t.Chdir("../../..")
+	r := newTestRouter()
+
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "<html") {
+		t.Errorf("expected the docs page instead of a plain-text 405, got %q", w.Body.String())
+	}
+}
+
+func TestRouter_DebugEcho(t *testing.T) {
+	prevConfig := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = prevConfig })
+
+	t.Run("disabled by default", func(t *testing.T) {
+		config.AppConfig = &config.Config{}
+		r := newTestRouter()
+
+		req, _ := http.NewRequest("GET", "/api/v1/debug/echo", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		config.AppConfig = &config.Config{DebugEndpoints: true}
+		r := newTestRouter()
+
+		req, _ := http.NewRequest("POST", "/api/v1/debug/echo?q=hello&tag=a&tag=b", nil)
+		req.Header.Set("Authorization", "Bearer secret-token")
+		req.Header.Set("X-Custom", "value")
+		req.Header.Set("X-Request-ID", "req-123")
+		req.RemoteAddr = "203.0.113.7:4321"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if strings.Contains(w.Body.String(), "secret-token") {
+			t.Errorf("expected Authorization header to be redacted, got %s", w.Body.String())
+		}
+
+		var response struct {
+			Data struct {
+				Method    string            `json:"method"`
+				Headers   map[string]string `json:"headers"`
+				Query     map[string]string `json:"query"`
+				ClientIP  string            `json:"client_ip"`
+				RequestID string            `json:"request_id"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to parse JSON response: %v", err)
+		}
+
+		data := response.Data
+		if data.Method != "POST" {
+			t.Errorf("expected method POST, got %q", data.Method)
+		}
+		if data.Headers["X-Custom"] != "value" {
+			t.Errorf("expected X-Custom header to be echoed, got %v", data.Headers)
+		}
+		if data.Headers["Authorization"] != "[REDACTED]" {
+			t.Errorf("expected Authorization to be [REDACTED], got %q", data.Headers["Authorization"])
+		}
+		if data.Query["q"] != "hello" || data.Query["tag"] != "a, b" {
+			t.Errorf("unexpected query params: %v", data.Query)
+		}
+		if data.ClientIP != "203.0.113.7" {
+			t.Errorf("expected client IP 203.0.113.7, got %q", data.ClientIP)
+		}
+		if data.RequestID != "req-123" {
+			t.Errorf("expected request ID req-123, got %q", data.RequestID)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"sort"
+	"strings"
+)
+
+// Errors maps a request field name to a human-readable message, letting handlers
+// report every invalid field at once instead of stopping at the first
+type Errors map[string]string
+
+func New() Errors {
+	return Errors{}
+}
+
+// Add records a message for field, keeping the first message if one already exists
+func (e Errors) Add(field, message string) {
+	if _, exists := e[field]; !exists {
+		e[field] = message
+	}
+}
+
+// Required records a "<field> is required" message when value is blank
+func (e Errors) Required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		e.Add(field, field+" is required")
+	}
+}
+
+func (e Errors) HasErrors() bool {
+	return len(e) > 0
+}
+
+// Error joins all messages in field order so Errors can be returned as an error
+func (e Errors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, 0, len(fields))
+	for _, field := range fields {
+		messages = append(messages, e[field])
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,44 @@
+package validation
+
+import "fmt"
+
+// DefaultMaxJSONDepth is the nesting limit used when none is configured
+const DefaultMaxJSONDepth = 32
+
+// DefaultMaxJSONBodyBytes is the request body size limit used when none is configured
+const DefaultMaxJSONBodyBytes = 1 << 20
+
+// CheckJSONDepth rejects data whose object/array nesting exceeds maxDepth. It only scans
+// bytes, so it is cheap to run before handing the body to a full decoder.
+func CheckJSONDepth(data []byte, maxDepth int) error {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for _, b := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case b == '\\':
+				escaped = true
+			case b == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch b {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxDepth {
+				return fmt.Errorf("request body exceeds maximum JSON nesting depth of %d", maxDepth)
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return nil
+}
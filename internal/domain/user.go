@@ -2,9 +2,20 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"net/mail"
+	"strings"
 	"time"
 )
 
+// MaxUserImportBatch caps the number of rows accepted by a single bulk import
+const MaxUserImportBatch = 1000
+
+var (
+	ErrInvalidEmail   = errors.New("invalid email")
+	ErrDuplicateEmail = errors.New("duplicate")
+)
+
 type User struct {
 	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
 	Name      string    `gorm:"type:varchar(255);not null" json:"name" binding:"required"`
@@ -13,16 +24,53 @@ type User struct {
 	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
 
+// UserImportRow is a single entry of a bulk user import payload
+type UserImportRow struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// UserImportResult reports the outcome of importing a single row
+type UserImportResult struct {
+	Index  int    `json:"index"`
+	Email  string `json:"email"`
+	Status string `json:"status"`
+	ID     uint   `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
 type UserRepository interface {
 	Create(ctx context.Context, user *User) error
+	// CreateBatch inserts users in one transaction, isolating each row with a savepoint.
+	// The returned slice holds a per-row error (nil on success) aligned with users.
+	CreateBatch(ctx context.Context, users []*User) ([]error, error)
 	FindAll(ctx context.Context) ([]User, error)
 	FindByID(ctx context.Context, id uint) (*User, error)
+	FindByEmail(ctx context.Context, email string) (*User, error)
 	Delete(ctx context.Context, id uint) error
+	// DeleteAll removes every user in one transaction and returns how many were deleted
+	DeleteAll(ctx context.Context) (int64, error)
 }
 
 type UserUsecase interface {
 	CreateUser(ctx context.Context, name, email string) (*User, error)
+	ImportUsers(ctx context.Context, rows []UserImportRow) ([]UserImportResult, error)
 	GetUsers(ctx context.Context) ([]User, error)
 	GetUserByID(ctx context.Context, id uint) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
 	DeleteUser(ctx context.Context, id uint) error
+	PurgeUsers(ctx context.Context) (int64, error)
+}
+
+// ValidateEmail checks that email is a bare, well-formed address (no display name)
+func ValidateEmail(email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return ErrInvalidEmail
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return ErrInvalidEmail
+	}
+	return nil
 }
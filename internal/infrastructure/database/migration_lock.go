@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"time"
+
+	"github.com/pressly/goose/v3/lock"
+)
+
+const (
+	// migrationLockTable holds a single row while a migrator runs, on dialects without advisory locks
+	migrationLockTable = "migration_lock"
+
+	migrationLockPollInterval = 100 * time.Millisecond
+
+	// migrationLockTTL is how long a lock row may go without a heartbeat before another migrator
+	// treats its owner as dead and takes the lock over
+	migrationLockTTL = 30 * time.Second
+)
+
+// newPostgresMigrationLocker returns goose's session-level advisory locker for the schema behind
+// tablePrefix. Instances sharing a prefix wait on each other; apps sharing the database under
+// other prefixes migrate independently.
+func newPostgresMigrationLocker(tablePrefix string) (lock.SessionLocker, error) {
+	return lock.NewPostgresSessionLocker(lock.WithLockID(migrationLockID(tablePrefix)))
+}
+
+// migrationLockID derives the advisory lock key from the prefixed goose version table
+func migrationLockID(tablePrefix string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(tablePrefix + gooseVersionTable))
+	return int64(h.Sum64())
+}
+
+// acquireTableLock claims the single row of table, polling while another migrator holds it.
+// The holder refreshes the row's heartbeat until it unlocks, so a row left behind by a crashed
+// process expires after migrationLockTTL instead of blocking every later run.
+func acquireTableLock(ctx context.Context, db *sql.DB, table string) (func() error, error) {
+	create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id INTEGER PRIMARY KEY, owner TEXT NOT NULL, heartbeat_at INTEGER NOT NULL)", table)
+	if _, err := db.ExecContext(ctx, create); err != nil {
+		return nil, err
+	}
+
+	// The owner token keeps a holder whose lock was taken over from touching its successor's row
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	owner := hex.EncodeToString(token)
+
+	insert := fmt.Sprintf("INSERT INTO %s (id, owner, heartbeat_at) VALUES (1, ?, ?)", table)
+	waiting := false
+	for {
+		_, err := db.ExecContext(ctx, insert, owner, time.Now().Unix())
+		if err == nil {
+			break
+		}
+
+		// Only a held lock is worth waiting for; anything else is a real failure
+		var holder string
+		var heartbeat int64
+		query := fmt.Sprintf("SELECT owner, heartbeat_at FROM %s WHERE id = 1", table)
+		if scanErr := db.QueryRowContext(ctx, query).Scan(&holder, &heartbeat); scanErr != nil {
+			return nil, err
+		}
+
+		if time.Since(time.Unix(heartbeat, 0)) > migrationLockTTL {
+			log.Printf("[database] migration lock has had no heartbeat for over %s, taking it over", migrationLockTTL)
+			// Matching owner and heartbeat keeps two waiters from both clearing a lock one of them just took
+			takeOver := fmt.Sprintf("DELETE FROM %s WHERE id = 1 AND owner = ? AND heartbeat_at = ?", table)
+			if _, err := db.ExecContext(ctx, takeOver, holder, heartbeat); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if !waiting {
+			log.Println("[database] another instance is migrating, waiting for the migration lock...")
+			waiting = true
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(migrationLockPollInterval):
+		}
+	}
+
+	heartbeatCtx, stopHeartbeat := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(migrationLockTTL / 3)
+		defer ticker.Stop()
+
+		refresh := fmt.Sprintf("UPDATE %s SET heartbeat_at = ? WHERE id = 1 AND owner = ?", table)
+		for {
+			select {
+			case <-heartbeatCtx.Done():
+				return
+			case <-ticker.C:
+				result, err := db.ExecContext(heartbeatCtx, refresh, time.Now().Unix(), owner)
+				if err != nil {
+					if heartbeatCtx.Err() == nil {
+						log.Printf("[database] warning: failed to refresh migration lock heartbeat: %v", err)
+					}
+					continue
+				}
+				if n, err := result.RowsAffected(); err == nil && n == 0 {
+					log.Println("[database] warning: migration lock was taken over by another instance")
+					return
+				}
+			}
+		}
+	}()
+
+	return func() error {
+		stopHeartbeat()
+		<-done
+		_, err := db.ExecContext(context.Background(), fmt.Sprintf("DELETE FROM %s WHERE id = 1 AND owner = ?", table), owner)
+		return err
+	}, nil
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+
+	"github.com/pressly/goose/v3"
+	goosedb "github.com/pressly/goose/v3/database"
+)
+
+// MigrationOptions tunes how pending migrations are applied
+type MigrationOptions struct {
+	// RollbackOnFailure reverts every migration applied during a run that fails partway,
+	// returning the schema to the version it had before the run started. Each migration
+	// already runs in its own transaction; this covers the migrations before the failing one.
+	RollbackOnFailure bool
+	// Lock serializes concurrent runs across instances so only one applies migrations;
+	// the others wait for it and then find nothing pending. Postgres uses goose's advisory
+	// session lock, other dialects a heartbeated lock row that goes stale if its owner dies.
+	Lock bool
+	// TablePrefix replaces ${DB_TABLE_PREFIX} in migration files and prefixes goose's version table
+	TablePrefix string
+}
+
+// RunMigrations applies all pending migrations found at the root of fsys
+func RunMigrations(ctx context.Context, db *sql.DB, dialect goose.Dialect, fsys fs.FS, opts MigrationOptions) error {
+	var providerOpts []goose.ProviderOption
+	if opts.Lock && dialect == goose.DialectPostgres {
+		locker, err := newPostgresMigrationLocker(opts.TablePrefix)
+		if err != nil {
+			return err
+		}
+		providerOpts = append(providerOpts, goose.WithSessionLocker(locker))
+	}
+
+	provider, err := newMigrationProvider(db, dialect, fsys, opts.TablePrefix, providerOpts...)
+	if err != nil {
+		return err
+	}
+
+	// Postgres is locked by goose itself; other dialects hold a lock row for the whole run
+	if opts.Lock && dialect != goose.DialectPostgres {
+		unlock, err := acquireTableLock(ctx, db, opts.TablePrefix+migrationLockTable)
+		if err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		defer func() {
+			if err := unlock(); err != nil {
+				log.Printf("[database] warning: failed to release migration lock: %v", err)
+			}
+		}()
+	}
+
+	results, err := provider.Up(ctx)
+	if err == nil {
+		for _, result := range results {
+			log.Printf("[database] applied migration %s (%s)", result.Source.Path, result.Duration)
+		}
+		return nil
+	}
+
+	// Only undo what this run applied; the failing migration's own transaction is already rolled back
+	var partial *goose.PartialError
+	if !opts.RollbackOnFailure || !errors.As(err, &partial) || len(partial.Applied) == 0 {
+		return err
+	}
+	startVersion := versionBefore(provider, partial.Applied[0].Source.Version)
+
+	log.Printf("[database] migration failed: %v. Rolling back to version %d...", err, startVersion)
+	if _, rbErr := provider.DownTo(ctx, startVersion); rbErr != nil {
+		return fmt.Errorf("migration failed: %w (rollback to version %d also failed: %v)", err, startVersion, rbErr)
+	}
+	log.Printf("[database] rolled back to version %d", startVersion)
+
+	return fmt.Errorf("migration failed, rolled back to version %d: %w", startVersion, err)
+}
+
+// versionBefore returns the version of the migration preceding version, or 0 when it is the first
+func versionBefore(provider *goose.Provider, version int64) int64 {
+	previous := int64(0)
+	for _, source := range provider.ListSources() {
+		if source.Version >= version {
+			break
+		}
+		previous = source.Version
+	}
+	return previous
+}
+
+// PlanMigrations lists the migrations in fsys that RunMigrations would apply, in order, without applying them.
+// It never writes to the database, not even to create goose's version table.
+func PlanMigrations(ctx context.Context, db *sql.DB, dialect goose.Dialect, fsys fs.FS, opts MigrationOptions) ([]string, error) {
+	provider, err := newMigrationProvider(db, dialect, fsys, opts.TablePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// goose creates its version table on first use, so only ask it for status once the table exists
+	exists, err := tableExistsIn(ctx, db, dialect, opts.TablePrefix+gooseVersionTable)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		pending := []string{}
+		for _, source := range provider.ListSources() {
+			pending = append(pending, path.Base(source.Path))
+		}
+		return pending, nil
+	}
+
+	statuses, err := provider.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []string{}
+	for _, status := range statuses {
+		if status.State == goose.StatePending {
+			pending = append(pending, path.Base(status.Source.Path))
+		}
+	}
+	return pending, nil
+}
+
+// tableExistsIn reports whether table exists, using a read-only catalog query for dialect
+func tableExistsIn(ctx context.Context, db *sql.DB, dialect goose.Dialect, table string) (bool, error) {
+	var exists bool
+	var err error
+	switch dialect {
+	case goose.DialectPostgres:
+		err = db.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists)
+	case goose.DialectSQLite3:
+		err = db.QueryRowContext(ctx, "SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&exists)
+	default:
+		return false, fmt.Errorf("planning migrations is not supported for dialect %q", dialect)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check for table %s: %w", table, err)
+	}
+	return exists, nil
+}
+
+func newMigrationProvider(db *sql.DB, dialect goose.Dialect, fsys fs.FS, tablePrefix string, opts ...goose.ProviderOption) (*goose.Provider, error) {
+	if err := validateTablePrefix(tablePrefix); err != nil {
+		return nil, err
+	}
+	fsys = prefixedFS{FS: fsys, prefix: tablePrefix}
+
+	if tablePrefix == "" {
+		return goose.NewProvider(dialect, db, fsys, opts...)
+	}
+
+	// goose only accepts a custom store when no dialect is passed alongside it
+	store, err := goosedb.NewStore(dialect, tablePrefix+gooseVersionTable)
+	if err != nil {
+		return nil, err
+	}
+	return goose.NewProvider("", db, fsys, append(opts, goose.WithStore(store))...)
+}
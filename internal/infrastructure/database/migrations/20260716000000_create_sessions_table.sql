@@ -1,5 +1,5 @@
 -- +goose Up
-CREATE TABLE sessions (
+CREATE TABLE ${DB_TABLE_PREFIX}sessions (
     id SERIAL PRIMARY KEY,
     token VARCHAR(255) UNIQUE NOT NULL,
     username VARCHAR(255) NOT NULL,
@@ -10,8 +10,8 @@ CREATE TABLE sessions (
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
-CREATE INDEX idx_sessions_token ON sessions(token);
-CREATE INDEX idx_sessions_username ON sessions(username);
+CREATE INDEX ${DB_TABLE_PREFIX}idx_sessions_token ON ${DB_TABLE_PREFIX}sessions(token);
+CREATE INDEX ${DB_TABLE_PREFIX}idx_sessions_username ON ${DB_TABLE_PREFIX}sessions(username);
 
 -- +goose Down
-DROP TABLE sessions;
+DROP TABLE ${DB_TABLE_PREFIX}sessions;
@@ -1,5 +1,5 @@
 -- +goose Up
-ALTER TABLE sessions ADD COLUMN session_hash VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE ${DB_TABLE_PREFIX}sessions ADD COLUMN session_hash VARCHAR(255) NOT NULL DEFAULT '';
 
 -- +goose Down
-ALTER TABLE sessions DROP COLUMN session_hash;
+ALTER TABLE ${DB_TABLE_PREFIX}sessions DROP COLUMN session_hash;
@@ -0,0 +1,255 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/abhay2133/api21/internal/infrastructure/database"
+	"github.com/pressly/goose/v3"
+	_ "modernc.org/sqlite"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	// A single connection keeps the in-memory database alive across queries
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func failingMigrations() fstest.MapFS {
+	return fstest.MapFS{
+		"00001_create_a.sql": {Data: []byte("-- +goose Up\nCREATE TABLE a (id INTEGER);\n-- +goose Down\nDROP TABLE a;\n")},
+		"00002_create_b.sql": {Data: []byte("-- +goose Up\nCREATE TABLE b (id INTEGER);\n-- +goose Down\nDROP TABLE b;\n")},
+		"00003_broken.sql":   {Data: []byte("-- +goose Up\nCREATE TABLE c (id INTEGER;\n-- +goose Down\nDROP TABLE c;\n")},
+	}
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count)
+	if err != nil {
+		t.Fatalf("failed to inspect schema: %v", err)
+	}
+	return count > 0
+}
+
+func dbVersion(t *testing.T, db *sql.DB) int64 {
+	t.Helper()
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, failingMigrations())
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	version, err := provider.GetDBVersion(context.Background())
+	if err != nil {
+		t.Fatalf("failed to read version: %v", err)
+	}
+	return version
+}
+
+func TestRunMigrations_RollbackOnFailure(t *testing.T) {
+	db := newTestDB(t)
+
+	err := database.RunMigrations(context.Background(), db, goose.DialectSQLite3, failingMigrations(), database.MigrationOptions{
+		RollbackOnFailure: true,
+	})
+	if err == nil {
+		t.Fatal("expected migration run to fail")
+	}
+
+	if v := dbVersion(t, db); v != 0 {
+		t.Errorf("expected schema rolled back to version 0, got %d", v)
+	}
+	if tableExists(t, db, "a") || tableExists(t, db, "b") {
+		t.Error("expected tables from earlier migrations to be rolled back")
+	}
+}
+
+func TestRunMigrations_WithoutRollback(t *testing.T) {
+	db := newTestDB(t)
+
+	err := database.RunMigrations(context.Background(), db, goose.DialectSQLite3, failingMigrations(), database.MigrationOptions{})
+	if err == nil {
+		t.Fatal("expected migration run to fail")
+	}
+
+	// The failing migration's own transaction is rolled back, earlier ones stay applied
+	if v := dbVersion(t, db); v != 2 {
+		t.Errorf("expected schema left at version 2, got %d", v)
+	}
+	if !tableExists(t, db, "b") || tableExists(t, db, "c") {
+		t.Error("expected only the failing migration to be undone")
+	}
+}
+
+func TestRunMigrations_ConcurrentRunsMigrateOnce(t *testing.T) {
+	db := newTestDB(t)
+
+	// Without the lock the second run would try to create table a again and fail
+	migrations := fstest.MapFS{
+		"00001_create_a.sql": {Data: []byte("-- +goose Up\nCREATE TABLE a (id INTEGER);\n-- +goose Down\nDROP TABLE a;\n")},
+		"00002_create_b.sql": {Data: []byte("-- +goose Up\nCREATE TABLE b (id INTEGER);\n-- +goose Down\nDROP TABLE b;\n")},
+	}
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			errs <- database.RunMigrations(context.Background(), db, goose.DialectSQLite3, migrations, database.MigrationOptions{
+				Lock: true,
+			})
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("expected concurrent migration runs to succeed, got %v", err)
+		}
+	}
+
+	var applied int
+	if err := db.QueryRow("SELECT COUNT(*) FROM goose_db_version WHERE version_id > 0").Scan(&applied); err != nil {
+		t.Fatalf("failed to count applied migrations: %v", err)
+	}
+	if applied != 2 {
+		t.Errorf("expected each migration to be applied exactly once, got %d applications", applied)
+	}
+	if v := dbVersion(t, db); v != 2 {
+		t.Errorf("expected schema at version 2, got %d", v)
+	}
+}
+
+func TestRunMigrations_TakesOverStaleLock(t *testing.T) {
+	db := newTestDB(t)
+
+	// A migrator that crashed an hour ago left its lock row behind
+	if _, err := db.Exec("CREATE TABLE migration_lock (id INTEGER PRIMARY KEY, owner TEXT NOT NULL, heartbeat_at INTEGER NOT NULL)"); err != nil {
+		t.Fatalf("failed to create lock table: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO migration_lock (id, owner, heartbeat_at) VALUES (1, 'crashed', ?)", time.Now().Add(-time.Hour).Unix()); err != nil {
+		t.Fatalf("failed to insert stale lock: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	migrations := fstest.MapFS{
+		"00001_create_a.sql": {Data: []byte("-- +goose Up\nCREATE TABLE a (id INTEGER);\n-- +goose Down\nDROP TABLE a;\n")},
+	}
+	if err := database.RunMigrations(ctx, db, goose.DialectSQLite3, migrations, database.MigrationOptions{Lock: true}); err != nil {
+		t.Fatalf("expected the stale lock to be taken over, got %v", err)
+	}
+	if !tableExists(t, db, "a") {
+		t.Error("expected the migration to be applied")
+	}
+
+	var held int
+	if err := db.QueryRow("SELECT COUNT(*) FROM migration_lock").Scan(&held); err != nil {
+		t.Fatalf("failed to inspect lock table: %v", err)
+	}
+	if held != 0 {
+		t.Errorf("expected the taken-over lock to be released, %d rows left", held)
+	}
+}
+
+func TestRunMigrations_TablePrefix(t *testing.T) {
+	db := newTestDB(t)
+
+	migrations := fstest.MapFS{
+		"00001_create_a.sql": {Data: []byte("-- +goose Up\nCREATE TABLE ${DB_TABLE_PREFIX}a (id INTEGER);\nCREATE INDEX ${DB_TABLE_PREFIX}idx_a_id ON ${DB_TABLE_PREFIX}a(id);\n-- +goose Down\nDROP TABLE ${DB_TABLE_PREFIX}a;\n")},
+	}
+
+	err := database.RunMigrations(context.Background(), db, goose.DialectSQLite3, migrations, database.MigrationOptions{
+		TablePrefix: "api21_",
+		Lock:        true,
+	})
+	if err != nil {
+		t.Fatalf("expected prefixed migrations to succeed, got %v", err)
+	}
+
+	for _, name := range []string{"api21_a", "api21_goose_db_version"} {
+		if !tableExists(t, db, name) {
+			t.Errorf("expected table %q to exist", name)
+		}
+	}
+	for _, name := range []string{"a", "goose_db_version"} {
+		if tableExists(t, db, name) {
+			t.Errorf("expected no unprefixed table %q", name)
+		}
+	}
+}
+
+func TestRunMigrations_RejectsUnsafeTablePrefix(t *testing.T) {
+	db := newTestDB(t)
+
+	err := database.RunMigrations(context.Background(), db, goose.DialectSQLite3, failingMigrations(), database.MigrationOptions{
+		TablePrefix: "x; DROP TABLE users; --",
+	})
+	if err == nil {
+		t.Fatal("expected an unsafe table prefix to be rejected")
+	}
+}
+
+func TestPlanMigrations(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	applied := fstest.MapFS{
+		"00001_create_a.sql": {Data: []byte("-- +goose Up\nCREATE TABLE a (id INTEGER);\n-- +goose Down\nDROP TABLE a;\n")},
+	}
+	if err := database.RunMigrations(ctx, db, goose.DialectSQLite3, applied, database.MigrationOptions{}); err != nil {
+		t.Fatalf("failed to apply first migration: %v", err)
+	}
+
+	all := fstest.MapFS{
+		"00001_create_a.sql": applied["00001_create_a.sql"],
+		"00002_create_b.sql": {Data: []byte("-- +goose Up\nCREATE TABLE b (id INTEGER);\n-- +goose Down\nDROP TABLE b;\n")},
+		"00003_create_c.sql": {Data: []byte("-- +goose Up\nCREATE TABLE c (id INTEGER);\n-- +goose Down\nDROP TABLE c;\n")},
+	}
+	pending, err := database.PlanMigrations(ctx, db, goose.DialectSQLite3, all, database.MigrationOptions{})
+	if err != nil {
+		t.Fatalf("failed to plan migrations: %v", err)
+	}
+
+	want := []string{"00002_create_b.sql", "00003_create_c.sql"}
+	if len(pending) != len(want) {
+		t.Fatalf("expected pending %v, got %v", want, pending)
+	}
+	for i := range want {
+		if pending[i] != want[i] {
+			t.Errorf("pending[%d]: expected %q, got %q", i, want[i], pending[i])
+		}
+	}
+
+	if v := dbVersion(t, db); v != 1 {
+		t.Errorf("expected planning to leave the schema at version 1, got %d", v)
+	}
+	if tableExists(t, db, "b") || tableExists(t, db, "c") {
+		t.Error("expected planning not to apply any migration")
+	}
+}
+
+func TestPlanMigrations_FreshDatabaseStaysUntouched(t *testing.T) {
+	db := newTestDB(t)
+
+	migrations := fstest.MapFS{
+		"00001_create_a.sql": {Data: []byte("-- +goose Up\nCREATE TABLE a (id INTEGER);\n-- +goose Down\nDROP TABLE a;\n")},
+		"00002_create_b.sql": {Data: []byte("-- +goose Up\nCREATE TABLE b (id INTEGER);\n-- +goose Down\nDROP TABLE b;\n")},
+	}
+	pending, err := database.PlanMigrations(context.Background(), db, goose.DialectSQLite3, migrations, database.MigrationOptions{})
+	if err != nil {
+		t.Fatalf("failed to plan migrations: %v", err)
+	}
+
+	if len(pending) != 2 || pending[0] != "00001_create_a.sql" || pending[1] != "00002_create_b.sql" {
+		t.Errorf("expected every migration to be pending, got %v", pending)
+	}
+	if tableExists(t, db, "goose_db_version") {
+		t.Error("expected planning not to create the version table")
+	}
+}
@@ -1,22 +1,32 @@
 package database
 
 import (
+	"context"
 	"embed"
+	"io/fs"
 	"log"
 	"time"
 
 	"github.com/pressly/goose/v3"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 //go:embed migrations/*.sql
 var embedMigrations embed.FS
 
-func NewPostgresConnection(dsn string) (*gorm.DB, error) {
+// Options configures the Postgres connection and its startup migrations
+type Options struct {
+	// TablePrefix namespaces every table so several apps can share one database
+	TablePrefix string
+	Migrations  MigrationOptions
+}
+
+func NewPostgresConnection(dsn string, opts Options) (*gorm.DB, error) {
 	log.Printf("[database] connecting to PostgreSQL...")
-	
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+
+	db, err := openPostgres(dsn, opts.TablePrefix)
 	if err != nil {
 		return nil, err
 	}
@@ -26,22 +36,92 @@ func NewPostgresConnection(dsn string) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	// Configure connection pool for scalability
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
-
 	log.Println("[database] connection established. Running migrations via Goose...")
-	
-	goose.SetBaseFS(embedMigrations)
-	if err := goose.SetDialect("postgres"); err != nil {
+
+	migrationsFS, err := embeddedMigrations()
+	if err != nil {
 		return nil, err
 	}
 
-	if err := goose.Up(sqlDB, "migrations"); err != nil {
+	migrationOpts := opts.Migrations
+	migrationOpts.TablePrefix = opts.TablePrefix
+	if err := RunMigrations(context.Background(), sqlDB, goose.DialectPostgres, migrationsFS, migrationOpts); err != nil {
 		return nil, err
 	}
-	
+
 	log.Println("[database] database migrations completed successfully.")
 	return db, nil
 }
+
+// NewPostgresReadConnection opens a connection to a read replica. Migrations only ever run against the primary.
+func NewPostgresReadConnection(dsn string, opts Options) (*gorm.DB, error) {
+	log.Printf("[database] connecting to PostgreSQL read replica...")
+
+	db, err := openPostgres(dsn, opts.TablePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Println("[database] read replica connection established.")
+	return db, nil
+}
+
+// PendingMigrations lists the embedded migrations not yet applied to db, without applying them
+func PendingMigrations(ctx context.Context, db *gorm.DB, tablePrefix string) ([]string, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	migrationsFS, err := embeddedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	return PlanMigrations(ctx, sqlDB, goose.DialectPostgres, migrationsFS, MigrationOptions{TablePrefix: tablePrefix})
+}
+
+// PlanPostgresMigrations connects to dsn without migrating it and lists the migrations it is missing
+func PlanPostgresMigrations(ctx context.Context, dsn string, opts Options) ([]string, error) {
+	db, err := openPostgres(dsn, opts.TablePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	defer sqlDB.Close()
+
+	return PendingMigrations(ctx, db, opts.TablePrefix)
+}
+
+func embeddedMigrations() (fs.FS, error) {
+	return fs.Sub(embedMigrations, "migrations")
+}
+
+func openPostgres(dsn, tablePrefix string) (*gorm.DB, error) {
+	if err := validateTablePrefix(tablePrefix); err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{TablePrefix: tablePrefix},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	// Configure connection pool for scalability
+	sqlDB.SetMaxIdleConns(10)
+	sqlDB.SetMaxOpenConns(100)
+	sqlDB.SetConnMaxLifetime(time.Hour)
+
+	return db, nil
+}
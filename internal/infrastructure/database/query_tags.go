@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const requestIDCommentPrefix = "/* reqid="
+
+type requestIDContextKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the ID of the HTTP request it serves
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDContextKey{}).(string)
+	return id, ok && id != ""
+}
+
+// RegisterQueryTagging prefixes every statement run with a request-scoped context with
+// /* reqid=... */ so slow queries in the database logs can be traced back to their request
+func RegisterQueryTagging(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().Before("gorm:create").Register("api21:tag_create", tagQuery("INSERT")); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("api21:tag_query", tagQuery("SELECT")); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("api21:tag_update", tagQuery("UPDATE")); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("api21:tag_delete", tagQuery("DELETE")); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("api21:tag_row", tagQuery("SELECT")); err != nil {
+		return err
+	}
+	return cb.Raw().Before("gorm:raw").Register("api21:tag_raw", tagQuery(""))
+}
+
+// tagQuery prepends the comment to SQL that is already built (Raw, Exec), and otherwise
+// attaches it in front of the statement's leading clause before gorm builds it
+func tagQuery(leadingClause string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		id, ok := RequestIDFromContext(db.Statement.Context)
+		if !ok || !IsValidRequestID(id) {
+			return
+		}
+		comment := requestIDCommentPrefix + id + " */"
+
+		if db.Statement.SQL.Len() > 0 {
+			sql := db.Statement.SQL.String()
+			if strings.HasPrefix(sql, requestIDCommentPrefix) {
+				return
+			}
+			db.Statement.SQL.Reset()
+			db.Statement.SQL.WriteString(comment + " " + sql)
+			return
+		}
+
+		if leadingClause == "" {
+			return
+		}
+		c := db.Statement.Clauses[leadingClause]
+		c.BeforeExpression = clause.Expr{SQL: comment}
+		db.Statement.Clauses[leadingClause] = c
+	}
+}
+
+// IsValidRequestID accepts short IDs that cannot close the SQL comment or be mistaken for a bind variable
+func IsValidRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		isAlnum := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !isAlnum && r != '-' && r != '_' && r != '.' && r != ':' {
+			return false
+		}
+	}
+	return true
+}
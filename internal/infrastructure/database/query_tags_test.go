@@ -0,0 +1,102 @@
+package database_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abhay2133/api21/internal/domain"
+	"github.com/abhay2133/api21/internal/infrastructure/database"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sqlRecorder is a gorm logger that keeps every traced statement
+type sqlRecorder struct {
+	logger.Interface
+	statements []string
+}
+
+func (r *sqlRecorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	r.statements = append(r.statements, sql)
+}
+
+func newTaggedDB(t *testing.T, tag bool) (*gorm.DB, *sqlRecorder) {
+	t.Helper()
+	recorder := &sqlRecorder{Interface: logger.Discard}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN: "host=127.0.0.1 user=postgres dbname=api21 sslmode=disable",
+	}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		// The default write transaction would dial the database even in dry-run mode
+		SkipDefaultTransaction: true,
+		Logger:                 recorder,
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry-run db: %v", err)
+	}
+	if tag {
+		if err := database.RegisterQueryTagging(db); err != nil {
+			t.Fatalf("failed to register query tagging: %v", err)
+		}
+	}
+	return db, recorder
+}
+
+func TestRegisterQueryTagging(t *testing.T) {
+	db, recorder := newTaggedDB(t, true)
+	ctx := database.ContextWithRequestID(context.Background(), "req-123")
+
+	for _, run := range []func(*gorm.DB) *gorm.DB{
+		func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]domain.User{}) },
+		func(tx *gorm.DB) *gorm.DB { return tx.Create(&domain.User{Name: "Alice", Email: "alice@example.com"}) },
+		func(tx *gorm.DB) *gorm.DB { return tx.Delete(&domain.User{}, 1) },
+		func(tx *gorm.DB) *gorm.DB { return tx.Exec("SELECT 1") },
+	} {
+		if err := run(db.WithContext(ctx)).Error; err != nil {
+			t.Fatalf("dry-run statement failed: %v", err)
+		}
+	}
+
+	if len(recorder.statements) != 4 {
+		t.Fatalf("expected 4 statements, got %d: %v", len(recorder.statements), recorder.statements)
+	}
+	for _, sql := range recorder.statements {
+		if !strings.HasPrefix(sql, "/* reqid=req-123 */ ") {
+			t.Errorf("expected statement to start with the request ID comment, got %q", sql)
+		}
+	}
+}
+
+func TestRegisterQueryTagging_Untagged(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  bool
+		ctx  context.Context
+	}{
+		{name: "disabled", tag: false, ctx: database.ContextWithRequestID(context.Background(), "req-123")},
+		{name: "no request ID", tag: true, ctx: context.Background()},
+		{name: "unsafe request ID", tag: true, ctx: database.ContextWithRequestID(context.Background(), "x */ DROP TABLE users; /*")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, recorder := newTaggedDB(t, tt.tag)
+
+			if err := db.WithContext(tt.ctx).Find(&[]domain.User{}).Error; err != nil {
+				t.Fatalf("dry-run query failed: %v", err)
+			}
+
+			if len(recorder.statements) != 1 {
+				t.Fatalf("expected 1 statement, got %d", len(recorder.statements))
+			}
+			if strings.Contains(recorder.statements[0], "/*") {
+				t.Errorf("expected no comment, got %q", recorder.statements[0])
+			}
+		})
+	}
+}
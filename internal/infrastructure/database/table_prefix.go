@@ -0,0 +1,67 @@
+package database
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+)
+
+// tablePrefixPlaceholder marks table and index names in migrations that take the configured prefix
+const tablePrefixPlaceholder = "${DB_TABLE_PREFIX}"
+
+// gooseVersionTable is goose's default version table, prefixed like every other table
+const gooseVersionTable = "goose_db_version"
+
+// validateTablePrefix only allows names that are safe to splice into unquoted SQL identifiers
+func validateTablePrefix(prefix string) error {
+	for _, r := range prefix {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return fmt.Errorf("invalid table prefix %q: only lowercase letters, digits and underscores are allowed", prefix)
+		}
+	}
+	return nil
+}
+
+// prefixedFS substitutes tablePrefixPlaceholder in every .sql file it serves
+type prefixedFS struct {
+	fs.FS
+	prefix string
+}
+
+func (p prefixedFS) Open(name string) (fs.File, error) {
+	f, err := p.FS.Open(name)
+	if err != nil || path.Ext(name) != ".sql" {
+		return f, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.ReplaceAll(data, []byte(tablePrefixPlaceholder), []byte(p.prefix))
+	return &substitutedFile{Reader: bytes.NewReader(data), info: substitutedInfo{FileInfo: info, size: int64(len(data))}}, nil
+}
+
+type substitutedFile struct {
+	*bytes.Reader
+	info fs.FileInfo
+}
+
+func (f *substitutedFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *substitutedFile) Close() error               { return nil }
+
+// substitutedInfo reports the size after substitution
+type substitutedInfo struct {
+	fs.FileInfo
+	size int64
+}
+
+func (i substitutedInfo) Size() int64 { return i.size }
@@ -2,34 +2,98 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/abhay2133/api21/internal/domain"
+	"github.com/abhay2133/api21/internal/infrastructure/database"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
+// pgUniqueViolation is the Postgres SQLSTATE for unique constraint violations
+const pgUniqueViolation = "23505"
+
 type userPostgresRepository struct {
 	db *gorm.DB
+	// readDB serves lookups and may be a replica; writes always go to db
+	readDB *gorm.DB
 }
 
-func NewUserPostgresRepository(db *gorm.DB) domain.UserRepository {
+// NewUserPostgresRepository routes reads to readDB, falling back to the primary db when it is nil
+func NewUserPostgresRepository(db, readDB *gorm.DB) domain.UserRepository {
+	if readDB == nil {
+		readDB = db
+	}
 	return &userPostgresRepository{
-		db: db,
+		db:     db,
+		readDB: readDB,
+	}
+}
+
+// writer joins the request's transaction when ctx carries one, otherwise it uses the primary
+func (r *userPostgresRepository) writer(ctx context.Context) *gorm.DB {
+	if tx, ok := database.TxFromContext(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+// reader also prefers the request's transaction so reads see its uncommitted writes
+func (r *userPostgresRepository) reader(ctx context.Context) *gorm.DB {
+	if tx, ok := database.TxFromContext(ctx); ok {
+		return tx.WithContext(ctx)
 	}
+	return r.readDB.WithContext(ctx)
 }
 
 func (r *userPostgresRepository) Create(ctx context.Context, user *domain.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return r.writer(ctx).Create(user).Error
+}
+
+func (r *userPostgresRepository) CreateBatch(ctx context.Context, users []*domain.User) ([]error, error) {
+	rowErrs := make([]error, len(users))
+
+	err := r.writer(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, user := range users {
+			savepoint := fmt.Sprintf("import_row_%d", i)
+			if err := tx.SavePoint(savepoint).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(user).Error; err != nil {
+				// Undo just this row so the rest of the batch can still commit
+				if rbErr := tx.RollbackTo(savepoint).Error; rbErr != nil {
+					return rbErr
+				}
+				rowErrs[i] = translateUserError(err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rowErrs, nil
 }
 
 func (r *userPostgresRepository) FindAll(ctx context.Context) ([]domain.User, error) {
 	var users []domain.User
-	err := r.db.WithContext(ctx).Find(&users).Error
+	err := r.reader(ctx).Find(&users).Error
 	return users, err
 }
 
 func (r *userPostgresRepository) FindByID(ctx context.Context, id uint) (*domain.User, error) {
 	var user domain.User
-	err := r.db.WithContext(ctx).First(&user, id).Error
+	err := r.reader(ctx).First(&user, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userPostgresRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	var user domain.User
+	err := r.reader(ctx).Where("email = ?", email).Take(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -37,5 +101,26 @@ func (r *userPostgresRepository) FindByID(ctx context.Context, id uint) (*domain
 }
 
 func (r *userPostgresRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&domain.User{}, id).Error
+	return r.writer(ctx).Delete(&domain.User{}, id).Error
+}
+
+func (r *userPostgresRepository) DeleteAll(ctx context.Context) (int64, error) {
+	var deleted int64
+	err := r.writer(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&domain.User{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+func translateUserError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return domain.ErrDuplicateEmail
+	}
+	return err
 }
@@ -0,0 +1,76 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abhay2133/api21/internal/domain"
+	"github.com/abhay2133/api21/internal/repository"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newRecordingDB opens a dry-run connection that counts the statements it is asked to run
+func newRecordingDB(t *testing.T) (*gorm.DB, *int) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN: "host=127.0.0.1 user=postgres dbname=api21 sslmode=disable",
+	}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		// The default write transaction would dial the database even in dry-run mode
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry-run db: %v", err)
+	}
+
+	count := 0
+	record := func(*gorm.DB) { count++ }
+	db.Callback().Query().Before("gorm:query").Register("test:record_query", record)
+	db.Callback().Create().Before("gorm:create").Register("test:record_create", record)
+	db.Callback().Delete().Before("gorm:delete").Register("test:record_delete", record)
+	return db, &count
+}
+
+func TestUserRepository_ReadWriteRouting(t *testing.T) {
+	primary, primaryCount := newRecordingDB(t)
+	replica, replicaCount := newRecordingDB(t)
+	repo := repository.NewUserPostgresRepository(primary, replica)
+	ctx := context.Background()
+
+	if _, err := repo.FindAll(ctx); err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	if _, err := repo.FindByID(ctx, 1); err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if _, err := repo.FindByEmail(ctx, "alice@example.com"); err != nil {
+		t.Fatalf("FindByEmail: %v", err)
+	}
+	if *replicaCount != 3 || *primaryCount != 0 {
+		t.Fatalf("expected 3 reads on replica and none on primary, got replica=%d primary=%d", *replicaCount, *primaryCount)
+	}
+
+	if err := repo.Create(ctx, &domain.User{Name: "Alice", Email: "alice@example.com"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if *primaryCount != 2 || *replicaCount != 3 {
+		t.Errorf("expected 2 writes on primary only, got replica=%d primary=%d", *replicaCount, *primaryCount)
+	}
+}
+
+func TestUserRepository_ReadsFallBackToPrimary(t *testing.T) {
+	primary, primaryCount := newRecordingDB(t)
+	repo := repository.NewUserPostgresRepository(primary, nil)
+
+	if _, err := repo.FindAll(context.Background()); err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	if *primaryCount != 1 {
+		t.Errorf("expected read on primary when no replica is configured, got %d", *primaryCount)
+	}
+}
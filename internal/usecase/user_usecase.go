@@ -3,6 +3,8 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
 	"strings"
 
 	"github.com/abhay2133/api21/internal/domain"
@@ -41,6 +43,80 @@ func (u *userUsecase) CreateUser(ctx context.Context, name, email string) (*doma
 	return user, nil
 }
 
+// importRowError turns a per-row save failure into a client-safe reason. Known domain errors pass
+// through; anything else may carry driver details, so it is logged and replaced with a generic message.
+func importRowError(index int, err error) string {
+	if errors.Is(err, domain.ErrDuplicateEmail) {
+		return domain.ErrDuplicateEmail.Error()
+	}
+	log.Printf("[ImportUsers] row %d could not be saved: %v", index, err)
+	return "could not be saved"
+}
+
+func (u *userUsecase) ImportUsers(ctx context.Context, rows []domain.UserImportRow) ([]domain.UserImportResult, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("import batch cannot be empty")
+	}
+	if len(rows) > domain.MaxUserImportBatch {
+		return nil, fmt.Errorf("import batch cannot exceed %d rows", domain.MaxUserImportBatch)
+	}
+
+	results := make([]domain.UserImportResult, len(rows))
+	seen := make(map[string]bool, len(rows))
+
+	var pending []*domain.User
+	var pendingIdx []int
+
+	for i, row := range rows {
+		name := strings.TrimSpace(row.Name)
+		email := strings.TrimSpace(strings.ToLower(row.Email))
+		results[i] = domain.UserImportResult{Index: i, Email: email}
+
+		if name == "" {
+			results[i].Status = "failed"
+			results[i].Error = "name cannot be empty"
+			continue
+		}
+		if err := domain.ValidateEmail(email); err != nil {
+			results[i].Status = "failed"
+			results[i].Error = err.Error()
+			continue
+		}
+		// Catch repeats inside the batch before they reach the unique index
+		if seen[email] {
+			results[i].Status = "failed"
+			results[i].Error = domain.ErrDuplicateEmail.Error()
+			continue
+		}
+		seen[email] = true
+
+		pending = append(pending, &domain.User{Name: name, Email: email})
+		pendingIdx = append(pendingIdx, i)
+	}
+
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	rowErrs, err := u.userRepo.CreateBatch(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, user := range pending {
+		i := pendingIdx[j]
+		if rowErrs[j] != nil {
+			results[i].Status = "failed"
+			results[i].Error = importRowError(i, rowErrs[j])
+			continue
+		}
+		results[i].Status = "created"
+		results[i].ID = user.ID
+	}
+
+	return results, nil
+}
+
 func (u *userUsecase) GetUsers(ctx context.Context) ([]domain.User, error) {
 	return u.userRepo.FindAll(ctx)
 }
@@ -49,6 +125,15 @@ func (u *userUsecase) GetUserByID(ctx context.Context, id uint) (*domain.User, e
 	return u.userRepo.FindByID(ctx, id)
 }
 
+func (u *userUsecase) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	// Emails are stored lower-cased by CreateUser, so normalize the lookup the same way
+	email = strings.TrimSpace(strings.ToLower(email))
+	if err := domain.ValidateEmail(email); err != nil {
+		return nil, err
+	}
+	return u.userRepo.FindByEmail(ctx, email)
+}
+
 func (u *userUsecase) DeleteUser(ctx context.Context, id uint) error {
 	// First ensure user exists
 	_, err := u.userRepo.FindByID(ctx, id)
@@ -57,3 +142,7 @@ func (u *userUsecase) DeleteUser(ctx context.Context, id uint) error {
 	}
 	return u.userRepo.Delete(ctx, id)
 }
+
+func (u *userUsecase) PurgeUsers(ctx context.Context) (int64, error) {
+	return u.userRepo.DeleteAll(ctx)
+}
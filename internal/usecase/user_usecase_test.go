@@ -13,6 +13,8 @@ import (
 type mockUserRepository struct {
 	users      []domain.User
 	shouldFail bool
+	// rowErrs fails CreateBatch rows by email with the given error
+	rowErrs map[string]error
 }
 
 func (m *mockUserRepository) Create(ctx context.Context, user *domain.User) error {
@@ -24,6 +26,31 @@ func (m *mockUserRepository) Create(ctx context.Context, user *domain.User) erro
 	return nil
 }
 
+func (m *mockUserRepository) CreateBatch(ctx context.Context, users []*domain.User) ([]error, error) {
+	if m.shouldFail {
+		return nil, errors.New("database connection failed")
+	}
+	rowErrs := make([]error, len(users))
+	for i, user := range users {
+		if err, ok := m.rowErrs[user.Email]; ok {
+			rowErrs[i] = err
+			continue
+		}
+		for _, existing := range m.users {
+			if existing.Email == user.Email {
+				rowErrs[i] = domain.ErrDuplicateEmail
+				break
+			}
+		}
+		if rowErrs[i] != nil {
+			continue
+		}
+		user.ID = uint(len(m.users) + 1)
+		m.users = append(m.users, *user)
+	}
+	return rowErrs, nil
+}
+
 func (m *mockUserRepository) FindAll(ctx context.Context) ([]domain.User, error) {
 	if m.shouldFail {
 		return nil, errors.New("database error")
@@ -43,6 +70,18 @@ func (m *mockUserRepository) FindByID(ctx context.Context, id uint) (*domain.Use
 	return nil, errors.New("user not found")
 }
 
+func (m *mockUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	if m.shouldFail {
+		return nil, errors.New("database error")
+	}
+	for _, u := range m.users {
+		if u.Email == email {
+			return &u, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
 func (m *mockUserRepository) Delete(ctx context.Context, id uint) error {
 	if m.shouldFail {
 		return errors.New("database error")
@@ -56,6 +95,15 @@ func (m *mockUserRepository) Delete(ctx context.Context, id uint) error {
 	return errors.New("user not found")
 }
 
+func (m *mockUserRepository) DeleteAll(ctx context.Context) (int64, error) {
+	if m.shouldFail {
+		return 0, errors.New("database error")
+	}
+	deleted := int64(len(m.users))
+	m.users = nil
+	return deleted, nil
+}
+
 func TestCreateUser(t *testing.T) {
 	repo := &mockUserRepository{}
 	uc := usecase.NewUserUsecase(repo)
@@ -114,3 +162,127 @@ func TestGetUsers(t *testing.T) {
 		t.Errorf("expected 2 users, got %d", len(users))
 	}
 }
+
+func TestImportUsers(t *testing.T) {
+	repo := &mockUserRepository{
+		users: []domain.User{
+			{ID: 1, Name: "Alice", Email: "alice@example.com"},
+		},
+	}
+	uc := usecase.NewUserUsecase(repo)
+	ctx := context.Background()
+
+	results, err := uc.ImportUsers(ctx, []domain.UserImportRow{
+		{Name: "Bob", Email: "Bob@Example.com"},
+		{Name: "Alice Again", Email: "alice@example.com"},
+		{Name: "Mallory", Email: "not-an-email"},
+		{Name: "Bob Twin", Email: "bob@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error importing users: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+
+	if results[0].Status != "created" || results[0].ID != 2 {
+		t.Errorf("expected row 0 to be created with ID 2, got %+v", results[0])
+	}
+	if results[0].Email != "bob@example.com" {
+		t.Errorf("expected row 0 email to be normalized, got %s", results[0].Email)
+	}
+	if results[1].Status != "failed" || results[1].Error != "duplicate" {
+		t.Errorf("expected row 1 to fail as duplicate, got %+v", results[1])
+	}
+	if results[2].Status != "failed" || results[2].Error != "invalid email" {
+		t.Errorf("expected row 2 to fail as invalid email, got %+v", results[2])
+	}
+	if results[3].Status != "failed" || results[3].Error != "duplicate" {
+		t.Errorf("expected row 3 to fail as in-batch duplicate, got %+v", results[3])
+	}
+
+	if len(repo.users) != 2 {
+		t.Errorf("expected 2 users stored, got %d", len(repo.users))
+	}
+
+	// Test empty batch
+	_, err = uc.ImportUsers(ctx, nil)
+	if err == nil {
+		t.Error("expected error for empty import batch, got nil")
+	}
+}
+
+func TestImportUsers_HidesStorageErrors(t *testing.T) {
+	repo := &mockUserRepository{
+		rowErrs: map[string]error{
+			"carol@example.com": errors.New(`ERROR: value too long for type character varying(255) (SQLSTATE 22001)`),
+		},
+	}
+	uc := usecase.NewUserUsecase(repo)
+
+	results, err := uc.ImportUsers(context.Background(), []domain.UserImportRow{
+		{Name: "Carol", Email: "carol@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error importing users: %v", err)
+	}
+	if results[0].Status != "failed" || results[0].Error != "could not be saved" {
+		t.Errorf("expected a generic failure reason, got %+v", results[0])
+	}
+}
+
+func TestGetUserByEmail(t *testing.T) {
+	repo := &mockUserRepository{
+		users: []domain.User{
+			{ID: 1, Name: "Alice", Email: "alice@example.com"},
+		},
+	}
+	uc := usecase.NewUserUsecase(repo)
+	ctx := context.Background()
+
+	u, err := uc.GetUserByEmail(ctx, "alice@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error fetching user by email: %v", err)
+	}
+	if u.ID != 1 {
+		t.Errorf("expected user ID 1, got %d", u.ID)
+	}
+
+	// Lookup is case-insensitive because stored emails are normalized
+	u, err = uc.GetUserByEmail(ctx, "  Alice@Example.COM ")
+	if err != nil || u.ID != 1 {
+		t.Errorf("expected case-variant email to match user 1, got %v (err: %v)", u, err)
+	}
+
+	_, err = uc.GetUserByEmail(ctx, "nobody@example.com")
+	if err == nil {
+		t.Error("expected not found error for unknown email, got nil")
+	}
+
+	_, err = uc.GetUserByEmail(ctx, "not-an-email")
+	if !errors.Is(err, domain.ErrInvalidEmail) {
+		t.Errorf("expected ErrInvalidEmail for malformed email, got %v", err)
+	}
+}
+
+func TestPurgeUsers(t *testing.T) {
+	repo := &mockUserRepository{
+		users: []domain.User{
+			{ID: 1, Name: "Alice", Email: "alice@example.com"},
+			{ID: 2, Name: "Charlie", Email: "charlie@example.com"},
+		},
+	}
+	uc := usecase.NewUserUsecase(repo)
+	ctx := context.Background()
+
+	deleted, err := uc.PurgeUsers(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error purging users: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("expected 2 users deleted, got %d", deleted)
+	}
+	if len(repo.users) != 0 {
+		t.Errorf("expected no users left, got %d", len(repo.users))
+	}
+}
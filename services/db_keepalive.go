@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+const keepaliveTimeout = 5 * time.Second
+
+// Pinger is satisfied by *sql.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// DBKeepalive pings the database on an interval so idle pooled connections stay warm
+// and a dead database is noticed before the next user request
+type DBKeepalive struct {
+	db       Pinger
+	interval time.Duration
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewDBKeepalive(db Pinger, interval time.Duration) *DBKeepalive {
+	return &DBKeepalive{
+		db:       db,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// StartDBKeepalive launches the keepalive loop, returning nil when disabled or no database is connected
+func StartDBKeepalive(db Pinger, interval time.Duration) *DBKeepalive {
+	if db == nil || interval <= 0 {
+		return nil
+	}
+
+	k := NewDBKeepalive(db, interval)
+	k.Start()
+	return k
+}
+
+// Start pings on every interval in a separate goroutine until Stop is called
+func (k *DBKeepalive) Start() {
+	log.Printf("[db:keepalive] started database keepalive every %s", k.interval)
+
+	go func() {
+		ticker := time.NewTicker(k.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				k.Ping()
+			case <-k.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Ping runs a single bounded ping, logging failures
+func (k *DBKeepalive) Ping() error {
+	if k.db == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keepaliveTimeout)
+	defer cancel()
+
+	if err := k.db.PingContext(ctx); err != nil {
+		log.Printf("[db:keepalive] database ping failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Stop ends the keepalive loop; it is safe to call more than once
+func (k *DBKeepalive) Stop() {
+	k.stopOnce.Do(func() {
+		close(k.stop)
+	})
+}
@@ -0,0 +1,58 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abhay2133/api21/services"
+)
+
+type countingPinger struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (p *countingPinger) PingContext(ctx context.Context) error {
+	p.calls.Add(1)
+	return p.err
+}
+
+func TestDBKeepalive_RunsOnInterval(t *testing.T) {
+	pinger := &countingPinger{}
+
+	k := services.StartDBKeepalive(pinger, 10*time.Millisecond)
+	if k == nil {
+		t.Fatalf("expected keepalive to start")
+	}
+	defer k.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for pinger.calls.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if pinger.calls.Load() < 2 {
+		t.Errorf("expected at least 2 pings, got %d", pinger.calls.Load())
+	}
+}
+
+func TestDBKeepalive_Disabled(t *testing.T) {
+	if k := services.StartDBKeepalive(nil, time.Second); k != nil {
+		t.Error("expected keepalive to be a no-op without a database")
+	}
+	if k := services.StartDBKeepalive(&countingPinger{}, 0); k != nil {
+		t.Error("expected keepalive to be a no-op with a zero interval")
+	}
+}
+
+func TestDBKeepalive_PingReportsFailure(t *testing.T) {
+	pinger := &countingPinger{err: errors.New("connection reset")}
+	k := services.NewDBKeepalive(pinger, time.Minute)
+
+	if err := k.Ping(); err == nil {
+		t.Error("expected ping failure to be returned")
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyReservoirSize bounds the samples kept per route for percentile estimates
+const latencyReservoirSize = 1024
+
+// RouteLatency summarizes the recorded latencies of one route, in milliseconds
+type RouteLatency struct {
+	Route string  `json:"route"`
+	Count int64   `json:"count"`
+	MinMs float64 `json:"min_ms"`
+	MaxMs float64 `json:"max_ms"`
+	AvgMs float64 `json:"avg_ms"`
+	P95Ms float64 `json:"p95_ms"`
+}
+
+type routeStats struct {
+	count   int64
+	total   time.Duration
+	min     time.Duration
+	max     time.Duration
+	samples []time.Duration
+}
+
+// LatencyTracker records per-route request latencies. Count, min, max and average are exact;
+// p95 comes from a uniform reservoir sample so memory per route stays bounded.
+type LatencyTracker struct {
+	mu     sync.Mutex
+	routes map[string]*routeStats
+	rng    *rand.Rand
+}
+
+func NewLatencyTracker() *LatencyTracker {
+	return &LatencyTracker{
+		routes: make(map[string]*routeStats),
+		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Record adds one observation for route
+func (t *LatencyTracker) Record(route string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.routes[route]
+	if !ok {
+		s = &routeStats{min: d, max: d}
+		t.routes[route] = s
+	}
+
+	s.count++
+	s.total += d
+	if d < s.min {
+		s.min = d
+	}
+	if d > s.max {
+		s.max = d
+	}
+
+	// Reservoir sampling keeps every observation equally likely to be in the sample
+	if len(s.samples) < latencyReservoirSize {
+		s.samples = append(s.samples, d)
+	} else if i := t.rng.Int63n(s.count); i < latencyReservoirSize {
+		s.samples[i] = d
+	}
+}
+
+// Snapshot returns the stats of every route, slowest p95 first
+func (t *LatencyTracker) Snapshot() []RouteLatency {
+	t.mu.Lock()
+	result := make([]RouteLatency, 0, len(t.routes))
+	for route, s := range t.routes {
+		samples := make([]time.Duration, len(s.samples))
+		copy(samples, s.samples)
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+		result = append(result, RouteLatency{
+			Route: route,
+			Count: s.count,
+			MinMs: toMs(s.min),
+			MaxMs: toMs(s.max),
+			AvgMs: toMs(s.total / time.Duration(s.count)),
+			P95Ms: toMs(samples[int(math.Ceil(0.95*float64(len(samples))))-1]),
+		})
+	}
+	t.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].P95Ms != result[j].P95Ms {
+			return result[i].P95Ms > result[j].P95Ms
+		}
+		return result[i].Route < result[j].Route
+	})
+	return result
+}
+
+func toMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,61 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abhay2133/api21/services"
+)
+
+func TestLatencyTracker_Snapshot(t *testing.T) {
+	tracker := services.NewLatencyTracker()
+
+	// 1ms..100ms once each, so p95 is 95ms
+	for i := 1; i <= 100; i++ {
+		tracker.Record("GET /api/v1/users", time.Duration(i)*time.Millisecond)
+	}
+	tracker.Record("GET /api/v1/health", 2*time.Millisecond)
+
+	snapshot := tracker.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("expected 2 routes, got %+v", snapshot)
+	}
+
+	users := snapshot[0]
+	if users.Route != "GET /api/v1/users" {
+		t.Fatalf("expected slowest route first, got %s", users.Route)
+	}
+	if users.Count != 100 {
+		t.Errorf("expected count 100, got %d", users.Count)
+	}
+	if users.MinMs != 1 || users.MaxMs != 100 {
+		t.Errorf("expected min 1ms and max 100ms, got %v and %v", users.MinMs, users.MaxMs)
+	}
+	if users.AvgMs != 50.5 {
+		t.Errorf("expected avg 50.5ms, got %v", users.AvgMs)
+	}
+	if users.P95Ms != 95 {
+		t.Errorf("expected p95 95ms, got %v", users.P95Ms)
+	}
+
+	health := snapshot[1]
+	if health.Count != 1 || health.P95Ms != 2 {
+		t.Errorf("unexpected single-sample stats: %+v", health)
+	}
+}
+
+func TestLatencyTracker_BoundedSamples(t *testing.T) {
+	tracker := services.NewLatencyTracker()
+
+	for i := 0; i < 5000; i++ {
+		tracker.Record("GET /slow", 10*time.Millisecond)
+	}
+
+	stats := tracker.Snapshot()[0]
+	if stats.Count != 5000 {
+		t.Errorf("expected exact count beyond the reservoir size, got %d", stats.Count)
+	}
+	if stats.P95Ms != 10 {
+		t.Errorf("expected p95 10ms, got %v", stats.P95Ms)
+	}
+}
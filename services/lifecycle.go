@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PhaseTiming is how long one lifecycle phase took
+type PhaseTiming struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Lifecycle times consecutive phases of a startup or shutdown sequence. Each Mark closes the
+// phase that began at the previous Mark (or at creation).
+type Lifecycle struct {
+	name string
+
+	mu       sync.Mutex
+	start    time.Time
+	lastMark time.Time
+	phases   []PhaseTiming
+}
+
+func NewLifecycle(name string) *Lifecycle {
+	now := time.Now()
+	return &Lifecycle{
+		name:     name,
+		start:    now,
+		lastMark: now,
+	}
+}
+
+// Started returns when the lifecycle began
+func (l *Lifecycle) Started() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.start
+}
+
+// Mark records the phase that just finished and logs its duration
+func (l *Lifecycle) Mark(phase string) {
+	l.mu.Lock()
+	now := time.Now()
+	timing := PhaseTiming{Name: phase, Duration: now.Sub(l.lastMark)}
+	l.phases = append(l.phases, timing)
+	l.lastMark = now
+	l.mu.Unlock()
+
+	log.Printf("[lifecycle] %s: %s took %s", l.name, phase, timing.Duration)
+}
+
+// Phases returns the recorded phases in order
+func (l *Lifecycle) Phases() []PhaseTiming {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	phases := make([]PhaseTiming, len(l.phases))
+	copy(phases, l.phases)
+	return phases
+}
+
+// Summary formats the total and per-phase durations as a single key=value line
+func (l *Lifecycle) Summary() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	parts := make([]string, 0, len(l.phases))
+	for _, phase := range l.phases {
+		parts = append(parts, fmt.Sprintf("%s=%s", strings.ReplaceAll(phase.Name, " ", "_"), phase.Duration))
+	}
+	return fmt.Sprintf("%s completed in %s: %s", l.name, l.lastMark.Sub(l.start), strings.Join(parts, " "))
+}
+
+// LogSummary writes Summary to the log
+func (l *Lifecycle) LogSummary() {
+	log.Printf("[lifecycle] %s", l.Summary())
+}
@@ -0,0 +1,42 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abhay2133/api21/services"
+)
+
+func TestLifecycle_RecordsPhases(t *testing.T) {
+	l := services.NewLifecycle("startup")
+
+	l.Mark("config")
+	time.Sleep(10 * time.Millisecond)
+	l.Mark("database")
+	l.Mark("http server")
+
+	phases := l.Phases()
+	want := []string{"config", "database", "http server"}
+	if len(phases) != len(want) {
+		t.Fatalf("expected %d phases, got %+v", len(want), phases)
+	}
+	for i, name := range want {
+		if phases[i].Name != name {
+			t.Errorf("phase %d: expected %q, got %q", i, name, phases[i].Name)
+		}
+		if phases[i].Duration < 0 {
+			t.Errorf("phase %q: expected non-negative duration, got %s", name, phases[i].Duration)
+		}
+	}
+	if phases[1].Duration < 10*time.Millisecond {
+		t.Errorf("expected database phase to cover the sleep, got %s", phases[1].Duration)
+	}
+
+	summary := l.Summary()
+	for _, key := range []string{"startup completed in", "config=", "database=", "http_server="} {
+		if !strings.Contains(summary, key) {
+			t.Errorf("expected summary to contain %q, got %q", key, summary)
+		}
+	}
+}
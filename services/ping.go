@@ -4,46 +4,135 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 )
 
-func StartPingWorker(pingURL string) {
-	if pingURL == "" {
-		return
-	}
+const (
+	pingInterval       = 60 * time.Second
+	pingTimeout        = 10 * time.Second
+	defaultHistorySize = 20
+)
 
-	log.Printf("[ping:server] started background ping worker for: %s", pingURL)
+// PingRun records the outcome of a single ping attempt
+type PingRun struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Success    bool      `json:"success"`
+	StatusCode int       `json:"status_code,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	Error      string    `json:"error,omitempty"`
+}
 
-	pingServer := func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
+// PingWorker periodically pings a URL and keeps a bounded history of recent runs
+type PingWorker struct {
+	url    string
+	client *http.Client
 
-		req, err := http.NewRequestWithContext(ctx, "GET", pingURL, nil)
-		if err != nil {
-			log.Printf("[ping:server] error creating request to %s: %v", pingURL, err)
-			return
-		}
+	mu      sync.Mutex
+	history []PingRun
+	next    int
+	count   int
+}
 
-		client := &http.Client{}
-		res, err := client.Do(req)
-		if err != nil {
-			log.Printf("[ping:server] error pinging %s: %v", pingURL, err)
-			return
-		}
-		defer res.Body.Close()
+// NewPingWorker creates a worker for pingURL. Internal (private, loopback, link-local) targets
+// are refused unless allowInternal is set.
+func NewPingWorker(pingURL string, historySize int, allowInternal bool) *PingWorker {
+	if historySize <= 0 {
+		historySize = defaultHistorySize
+	}
+	return &PingWorker{
+		url:     pingURL,
+		client:  newPingClient(allowInternal),
+		history: make([]PingRun, historySize),
+	}
+}
 
-		log.Printf("[ping:server] %s → %s", pingURL, res.Status)
+// StartPingWorker launches the background ping loop, returning nil when no URL is configured
+func StartPingWorker(pingURL string, allowInternal bool) *PingWorker {
+	if pingURL == "" {
+		return nil
 	}
 
-	// Run on startup in a separate goroutine
+	w := NewPingWorker(pingURL, defaultHistorySize, allowInternal)
+	w.Start()
+	return w
+}
+
+// Start runs a ping immediately and then on every interval in a separate goroutine
+func (w *PingWorker) Start() {
+	log.Printf("[ping:server] started background ping worker for: %s", w.url)
+
 	go func() {
-		pingServer()
+		w.Ping()
 
-		ticker := time.NewTicker(60 * time.Second)
+		ticker := time.NewTicker(pingInterval)
 		defer ticker.Stop()
 
 		for range ticker.C {
-			pingServer()
+			w.Ping()
 		}
 	}()
 }
+
+// Ping performs a single request against the configured URL and records the result
+func (w *PingWorker) Ping() PingRun {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	run := PingRun{Timestamp: time.Now()}
+	defer func() {
+		w.record(run)
+	}()
+
+	if err := validatePingURL(w.url); err != nil {
+		log.Printf("[ping:server] refusing to ping %s: %v", w.url, err)
+		run.Error = err.Error()
+		return run
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", w.url, nil)
+	if err != nil {
+		log.Printf("[ping:server] error creating request to %s: %v", w.url, err)
+		run.Error = err.Error()
+		return run
+	}
+
+	res, err := w.client.Do(req)
+	run.DurationMs = time.Since(run.Timestamp).Milliseconds()
+	if err != nil {
+		log.Printf("[ping:server] error pinging %s: %v", w.url, err)
+		run.Error = err.Error()
+		return run
+	}
+	defer res.Body.Close()
+
+	run.StatusCode = res.StatusCode
+	run.Success = res.StatusCode < http.StatusBadRequest
+
+	log.Printf("[ping:server] %s → %s", w.url, res.Status)
+	return run
+}
+
+func (w *PingWorker) record(run PingRun) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.history[w.next] = run
+	w.next = (w.next + 1) % len(w.history)
+	if w.count < len(w.history) {
+		w.count++
+	}
+}
+
+// History returns the recorded runs, oldest first
+func (w *PingWorker) History() []PingRun {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	runs := make([]PingRun, 0, w.count)
+	start := (w.next - w.count + len(w.history)) % len(w.history)
+	for i := 0; i < w.count; i++ {
+		runs = append(runs, w.history[(start+i)%len(w.history)])
+	}
+	return runs
+}
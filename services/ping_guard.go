@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrInternalTarget is returned when a ping would reach a private, loopback or link-local address
+var ErrInternalTarget = errors.New("ping target resolves to an internal address")
+
+// cgnatRange is the carrier-grade NAT block, which net.IP.IsPrivate does not cover
+var cgnatRange = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsUnspecified() ||
+		cgnatRange.Contains(ip)
+}
+
+// validatePingURL only allows absolute http(s) URLs
+func validatePingURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported ping URL scheme %q, expected http or https", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return errors.New("ping URL has no host")
+	}
+	return nil
+}
+
+// newPingClient returns a client that, unless allowInternal is set, refuses to connect to
+// internal addresses. The check runs on the resolved IP at dial time, so DNS tricks and
+// redirects to internal hosts are caught as well.
+func newPingClient(allowInternal bool) *http.Client {
+	if allowInternal {
+		return &http.Client{}
+	}
+
+	dialer := &net.Dialer{
+		Timeout: pingTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || isInternalIP(ip) {
+				return ErrInternalTarget
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			// No proxy, otherwise the dial check would see the proxy instead of the target
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+	}
+}
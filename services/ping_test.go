@@ -0,0 +1,98 @@
+package services_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abhay2133/api21/services"
+)
+
+func TestPingWorker_History(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	w := services.NewPingWorker(server.URL, 2, true)
+
+	if len(w.History()) != 0 {
+		t.Fatalf("expected empty history before any run")
+	}
+
+	w.Ping()
+	status = http.StatusServiceUnavailable
+	w.Ping()
+
+	history := w.History()
+	if len(history) != 2 {
+		t.Fatalf("expected 2 runs in history, got %d", len(history))
+	}
+	if !history[0].Success || history[0].StatusCode != http.StatusOK {
+		t.Errorf("expected first run to succeed with 200, got %+v", history[0])
+	}
+	if history[1].Success || history[1].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected second run to fail with 503, got %+v", history[1])
+	}
+
+	// A third run evicts the oldest entry from the ring buffer
+	status = http.StatusNoContent
+	w.Ping()
+
+	history = w.History()
+	if len(history) != 2 {
+		t.Fatalf("expected history to stay bounded at 2, got %d", len(history))
+	}
+	if history[0].StatusCode != http.StatusServiceUnavailable || history[1].StatusCode != http.StatusNoContent {
+		t.Errorf("expected history ordered oldest first after wrap, got %+v", history)
+	}
+}
+
+func TestPingWorker_RecordsTransportErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	w := services.NewPingWorker(url, 0, true)
+	run := w.Ping()
+
+	if run.Success || run.Error == "" {
+		t.Errorf("expected failed run with an error, got %+v", run)
+	}
+	if len(w.History()) != 1 {
+		t.Errorf("expected failed run to be recorded")
+	}
+}
+
+func TestPingWorker_BlocksInternalTargets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	for _, target := range []string{
+		"http://169.254.169.254/latest/meta-data/",
+		server.URL,
+		"http://localhost" + server.URL[strings.LastIndex(server.URL, ":"):],
+		"ftp://example.com/file",
+	} {
+		run := services.NewPingWorker(target, 0, false).Ping()
+		if run.Success || run.Error == "" {
+			t.Errorf("expected ping to %s to be refused by default, got %+v", target, run)
+		}
+	}
+}
+
+func TestPingWorker_AllowsInternalTargetsWhenEnabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	run := services.NewPingWorker(server.URL, 0, true).Ping()
+	if !run.Success {
+		t.Errorf("expected ping to local server to succeed with internal targets allowed, got %+v", run)
+	}
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DrainAndShutdown marks the instance not ready, keeps serving for the drain period so load
+// balancers stop routing new traffic to it, then calls shutdown. Cancelling ctx cuts the drain short.
+func DrainAndShutdown(ctx context.Context, drain time.Duration, markNotReady func(), shutdown func(context.Context) error) error {
+	markNotReady()
+
+	if drain > 0 {
+		log.Printf("[shutdown] draining for %s before shutdown", drain)
+		timer := time.NewTimer(drain)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			log.Printf("[shutdown] drain interrupted: %v", ctx.Err())
+		}
+	}
+
+	log.Println("[shutdown] shutting down server...")
+	return shutdown(ctx)
+}
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/abhay2133/api21/services"
+)
+
+func TestDrainAndShutdown(t *testing.T) {
+	const drain = 50 * time.Millisecond
+
+	var notReadyAt, shutdownAt time.Time
+	start := time.Now()
+
+	err := services.DrainAndShutdown(context.Background(), drain,
+		func() { notReadyAt = time.Now() },
+		func(ctx context.Context) error {
+			shutdownAt = time.Now()
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if notReadyAt.IsZero() || shutdownAt.IsZero() {
+		t.Fatal("expected both readiness and shutdown hooks to run")
+	}
+	if shutdownAt.Before(notReadyAt) {
+		t.Error("expected readiness to drop before shutdown")
+	}
+	if shutdownAt.Sub(start) < drain {
+		t.Errorf("expected shutdown after the %s drain, got %s", drain, shutdownAt.Sub(start))
+	}
+}
+
+func TestDrainAndShutdown_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	shutdownCalled := false
+	start := time.Now()
+	services.DrainAndShutdown(ctx, time.Minute, func() {}, func(ctx context.Context) error {
+		shutdownCalled = true
+		return nil
+	})
+
+	if !shutdownCalled {
+		t.Error("expected shutdown to run after an interrupted drain")
+	}
+	if time.Since(start) > time.Second {
+		t.Error("expected cancelled context to cut the drain short")
+	}
+}
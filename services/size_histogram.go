@@ -0,0 +1,107 @@
+package services
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultSizeBuckets are the histogram upper bounds, in bytes, used when none are configured
+var DefaultSizeBuckets = []int64{256, 1024, 4096, 16384, 65536, 262144, 1048576}
+
+// SizeBucket counts the bodies no larger than Le bytes and larger than the previous bucket's bound
+type SizeBucket struct {
+	Le    string `json:"le"`
+	Count int64  `json:"count"`
+}
+
+// SizeDistribution is the histogram of one direction of traffic
+type SizeDistribution struct {
+	Count      int64        `json:"count"`
+	TotalBytes int64        `json:"total_bytes"`
+	Buckets    []SizeBucket `json:"buckets"`
+}
+
+// SizeSnapshot holds the request and response body size histograms
+type SizeSnapshot struct {
+	Request  SizeDistribution `json:"request"`
+	Response SizeDistribution `json:"response"`
+}
+
+type sizeCounts struct {
+	count   int64
+	total   int64
+	buckets []int64
+}
+
+// SizeHistogram records request and response body sizes into fixed buckets plus a final +Inf bucket
+type SizeHistogram struct {
+	bounds []int64
+
+	mu       sync.Mutex
+	request  sizeCounts
+	response sizeCounts
+}
+
+// NewSizeHistogram uses the given upper bounds in bytes, falling back to DefaultSizeBuckets when empty
+func NewSizeHistogram(bounds []int64) *SizeHistogram {
+	if len(bounds) == 0 {
+		bounds = DefaultSizeBuckets
+	}
+	sorted := make([]int64, len(bounds))
+	copy(sorted, bounds)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &SizeHistogram{
+		bounds:   sorted,
+		request:  sizeCounts{buckets: make([]int64, len(sorted)+1)},
+		response: sizeCounts{buckets: make([]int64, len(sorted)+1)},
+	}
+}
+
+// Record adds one request/response pair. Negative sizes (unknown) are skipped.
+func (h *SizeHistogram) Record(requestBytes, responseBytes int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.observe(&h.request, requestBytes)
+	h.observe(&h.response, responseBytes)
+}
+
+func (h *SizeHistogram) observe(counts *sizeCounts, size int64) {
+	if size < 0 {
+		return
+	}
+	// First bound >= size; len(bounds) selects the +Inf bucket
+	i := sort.Search(len(h.bounds), func(i int) bool { return h.bounds[i] >= size })
+	counts.buckets[i]++
+	counts.count++
+	counts.total += size
+}
+
+// Snapshot returns a copy of both histograms
+func (h *SizeHistogram) Snapshot() SizeSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return SizeSnapshot{
+		Request:  h.distribution(h.request),
+		Response: h.distribution(h.response),
+	}
+}
+
+func (h *SizeHistogram) distribution(counts sizeCounts) SizeDistribution {
+	buckets := make([]SizeBucket, len(counts.buckets))
+	for i, n := range counts.buckets {
+		le := "+Inf"
+		if i < len(h.bounds) {
+			le = strconv.FormatInt(h.bounds[i], 10)
+		}
+		buckets[i] = SizeBucket{Le: le, Count: n}
+	}
+	return SizeDistribution{
+		Count:      counts.count,
+		TotalBytes: counts.total,
+		Buckets:    buckets,
+	}
+}
@@ -0,0 +1,50 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/abhay2133/api21/services"
+)
+
+func TestSizeHistogram_Buckets(t *testing.T) {
+	h := services.NewSizeHistogram([]int64{1000, 100})
+
+	h.Record(0, 50)
+	h.Record(100, 101)
+	h.Record(5000, 1000)
+	h.Record(-1, 20000)
+
+	snap := h.Snapshot()
+
+	wantRequest := []services.SizeBucket{{Le: "100", Count: 2}, {Le: "1000", Count: 0}, {Le: "+Inf", Count: 1}}
+	wantResponse := []services.SizeBucket{{Le: "100", Count: 1}, {Le: "1000", Count: 2}, {Le: "+Inf", Count: 1}}
+
+	for _, tt := range []struct {
+		name string
+		got  services.SizeDistribution
+		want []services.SizeBucket
+	}{
+		{name: "request", got: snap.Request, want: wantRequest},
+		{name: "response", got: snap.Response, want: wantResponse},
+	} {
+		if len(tt.got.Buckets) != len(tt.want) {
+			t.Fatalf("%s: expected %d buckets, got %+v", tt.name, len(tt.want), tt.got.Buckets)
+		}
+		for i := range tt.want {
+			if tt.got.Buckets[i] != tt.want[i] {
+				t.Errorf("%s bucket %d: expected %+v, got %+v", tt.name, i, tt.want[i], tt.got.Buckets[i])
+			}
+		}
+	}
+
+	if snap.Request.Count != 3 || snap.Request.TotalBytes != 5100 {
+		t.Errorf("expected unknown request sizes to be skipped, got count=%d total=%d", snap.Request.Count, snap.Request.TotalBytes)
+	}
+}
+
+func TestSizeHistogram_DefaultBuckets(t *testing.T) {
+	snap := services.NewSizeHistogram(nil).Snapshot()
+	if len(snap.Request.Buckets) != len(services.DefaultSizeBuckets)+1 {
+		t.Errorf("expected default buckets plus +Inf, got %+v", snap.Request.Buckets)
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// trafficBuckets splits the window so counts slide in steps of window/trafficBuckets
+	trafficBuckets       = 12
+	defaultMaxTrackedIPs = 10000
+)
+
+// IPTraffic is the request count of one client IP within the current window
+type IPTraffic struct {
+	IP       string `json:"ip"`
+	Requests int64  `json:"requests"`
+}
+
+type ipWindow struct {
+	ip       string
+	counts   [trafficBuckets]int64
+	slots    [trafficBuckets]int64
+	lastSeen time.Time
+}
+
+// TrafficCounter counts requests per client IP over a sliding window, tracking at most maxIPs addresses
+type TrafficCounter struct {
+	window      time.Duration
+	bucketWidth time.Duration
+	maxIPs      int
+
+	mu  sync.Mutex
+	ips map[string]*list.Element
+	// lru orders the tracked IPs by last request, most recent at the front, so eviction and
+	// pruning only touch the stale end
+	lru       *list.List
+	lastPrune time.Time
+}
+
+func NewTrafficCounter(window time.Duration, maxIPs int) *TrafficCounter {
+	if maxIPs <= 0 {
+		maxIPs = defaultMaxTrackedIPs
+	}
+	bucketWidth := window / trafficBuckets
+	if bucketWidth <= 0 {
+		bucketWidth = time.Nanosecond
+	}
+	return &TrafficCounter{
+		window:      window,
+		bucketWidth: bucketWidth,
+		maxIPs:      maxIPs,
+		ips:         make(map[string]*list.Element),
+		lru:         list.New(),
+		lastPrune:   time.Now(),
+	}
+}
+
+// StartTrafficCounter returns a counter for the given window, or nil when the window is zero (disabled)
+func StartTrafficCounter(window time.Duration) *TrafficCounter {
+	if window <= 0 {
+		return nil
+	}
+	return NewTrafficCounter(window, defaultMaxTrackedIPs)
+}
+
+// Record counts one request from ip
+func (t *TrafficCounter) Record(ip string) {
+	t.record(ip, time.Now())
+}
+
+func (t *TrafficCounter) record(ip string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.lastPrune) >= t.window {
+		t.pruneLocked(now)
+	}
+
+	var w *ipWindow
+	if elem, ok := t.ips[ip]; ok {
+		w = elem.Value.(*ipWindow)
+		t.lru.MoveToFront(elem)
+	} else {
+		if len(t.ips) >= t.maxIPs {
+			t.evictLocked(t.lru.Back())
+		}
+		w = &ipWindow{ip: ip}
+		t.ips[ip] = t.lru.PushFront(w)
+	}
+
+	slot := now.UnixNano() / int64(t.bucketWidth)
+	idx := slot % trafficBuckets
+	if w.slots[idx] != slot {
+		w.slots[idx] = slot
+		w.counts[idx] = 0
+	}
+	w.counts[idx]++
+	w.lastSeen = now
+}
+
+// Top returns up to n of the busiest IPs in the current window, busiest first
+func (t *TrafficCounter) Top(n int) []IPTraffic {
+	now := time.Now()
+	current := now.UnixNano() / int64(t.bucketWidth)
+
+	t.mu.Lock()
+	result := make([]IPTraffic, 0, len(t.ips))
+	for ip, elem := range t.ips {
+		w := elem.Value.(*ipWindow)
+		var total int64
+		for i, slot := range w.slots {
+			if slot > current-trafficBuckets {
+				total += w.counts[i]
+			}
+		}
+		if total > 0 {
+			result = append(result, IPTraffic{IP: ip, Requests: total})
+		}
+	}
+	t.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Requests != result[j].Requests {
+			return result[i].Requests > result[j].Requests
+		}
+		return result[i].IP < result[j].IP
+	})
+	if n > 0 && len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// pruneLocked drops IPs that have not been seen within the window, walking from the least recent
+func (t *TrafficCounter) pruneLocked(now time.Time) {
+	for elem := t.lru.Back(); elem != nil; elem = t.lru.Back() {
+		if now.Sub(elem.Value.(*ipWindow).lastSeen) < t.window {
+			break
+		}
+		t.evictLocked(elem)
+	}
+	t.lastPrune = now
+}
+
+func (t *TrafficCounter) evictLocked(elem *list.Element) {
+	if elem == nil {
+		return
+	}
+	t.lru.Remove(elem)
+	delete(t.ips, elem.Value.(*ipWindow).ip)
+}
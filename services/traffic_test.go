@@ -0,0 +1,84 @@
+package services_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abhay2133/api21/services"
+)
+
+func TestTrafficCounter_Top(t *testing.T) {
+	c := services.NewTrafficCounter(time.Minute, 0)
+
+	hits := map[string]int{
+		"10.0.0.1": 3,
+		"10.0.0.2": 7,
+		"10.0.0.3": 1,
+		"10.0.0.4": 5,
+	}
+
+	var wg sync.WaitGroup
+	for ip, n := range hits {
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(ip string) {
+				defer wg.Done()
+				c.Record(ip)
+			}(ip)
+		}
+	}
+	wg.Wait()
+
+	top := c.Top(3)
+	want := []services.IPTraffic{
+		{IP: "10.0.0.2", Requests: 7},
+		{IP: "10.0.0.4", Requests: 5},
+		{IP: "10.0.0.1", Requests: 3},
+	}
+	if len(top) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(top), top)
+	}
+	for i := range want {
+		if top[i] != want[i] {
+			t.Errorf("rank %d: expected %+v, got %+v", i, want[i], top[i])
+		}
+	}
+}
+
+func TestTrafficCounter_BoundedIPs(t *testing.T) {
+	c := services.NewTrafficCounter(time.Minute, 2)
+
+	c.Record("10.0.0.1")
+	c.Record("10.0.0.2")
+	c.Record("10.0.0.2")
+	c.Record("10.0.0.3")
+
+	top := c.Top(0)
+	if len(top) != 2 {
+		t.Fatalf("expected counter to track at most 2 IPs, got %+v", top)
+	}
+	for _, entry := range top {
+		if entry.IP == "10.0.0.1" {
+			t.Errorf("expected least recently seen IP to be evicted, got %+v", top)
+		}
+	}
+}
+
+func TestTrafficCounter_EvictsLeastRecentlySeen(t *testing.T) {
+	c := services.NewTrafficCounter(time.Minute, 2)
+
+	c.Record("10.0.0.1")
+	c.Record("10.0.0.2")
+	// Seeing 10.0.0.1 again makes 10.0.0.2 the least recent, even though it arrived later
+	c.Record("10.0.0.1")
+	c.Record("10.0.0.3")
+
+	seen := map[string]bool{}
+	for _, entry := range c.Top(0) {
+		seen[entry.IP] = true
+	}
+	if !seen["10.0.0.1"] || !seen["10.0.0.3"] || seen["10.0.0.2"] {
+		t.Errorf("expected 10.0.0.2 to be evicted, got %v", seen)
+	}
+}